@@ -5,6 +5,7 @@ import (
 	"Start/internal/app"
 	"Start/internal/migration"
 	"Start/internal/shared/database"
+	"context"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -29,6 +30,7 @@ func main() {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	app.RegisterModules(r, db)
+	app.StartScheduler(context.Background(), db)
 
 	err := r.Run(":8080")
 	if err != nil {
@@ -13,9 +13,18 @@ func RegisterAdminRoutes(rg *gin.RouterGroup, handler *handler.AdminHandler) {
 	admin.GET("/users", handler.GetAllUsers)
 	admin.GET("/purchases", handler.GetAllPurchases)
 	admin.GET("/redemptions", handler.GetAllRedemptions)
+	admin.GET("/redemptions/queue", handler.GetRedemptionQueue)
+	admin.GET("/notifications", handler.GetNotifications)
+	admin.GET("/inventory/forecast", handler.GetStockForecast)
+	admin.GET("/inventory/wishlisted", handler.GetWishlistedOutOfStock)
 
 	admin.PUT("/redemptions/:id/status", handler.UpdateRedemptionStatus)
+	admin.POST("/redemptions/bulk", handler.BulkUpdateRedemptions)
+	admin.POST("/redemptions/:id/claim", handler.ClaimRedemption)
+	admin.PUT("/redemptions/:id/assignee", handler.AssignRedemption)
 	admin.POST("/users/:id/credits", handler.ManageUserCredits)
 	admin.POST("/users/:id/points", handler.ManageUserPoints)
 	admin.PUT("/users/:id/status", handler.ModerateUser)
+	admin.POST("/users/:id/unlock", handler.UnlockUser)
+	admin.PUT("/notifications/:id/read", handler.MarkNotificationRead)
 }
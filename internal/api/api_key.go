@@ -0,0 +1,15 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAPIKeyRoutes(rg *gin.RouterGroup, handler *handler.APIKeyHandler) {
+	keys := rg.Group("/admin/api-keys", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	keys.GET("", handler.ListAPIKeys)
+	keys.POST("", handler.CreateAPIKey)
+	keys.DELETE("/:id", handler.RevokeAPIKey)
+}
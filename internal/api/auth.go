@@ -12,5 +12,12 @@ func RegisterAuthRoutes(rg *gin.RouterGroup, handler *handler.AuthHandler) {
 	auth.POST("/signup", handler.SignUp)
 	auth.POST("/login", handler.Login)
 	auth.POST("/refresh", handler.RefreshToken)
+	auth.POST("/login/2fa", handler.LoginWithMFA)
 	auth.PUT("/change-password", middleware.AuthMiddleware(), handler.ChangePassword)
+
+	twoFactor := auth.Group("/2fa", middleware.AuthMiddleware())
+	twoFactor.POST("/enroll", handler.EnrollTOTP)
+	twoFactor.POST("/verify", handler.ConfirmTOTP)
+	twoFactor.POST("/disable", handler.DisableTOTP)
+	twoFactor.POST("/recovery-codes", handler.RegenerateRecoveryCodes)
 }
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCartRoutes(rg *gin.RouterGroup, handler *handler.CartHandler) {
+	cart := rg.Group("/cart", middleware.AuthMiddleware())
+
+	cart.GET("", handler.GetCart)
+	cart.DELETE("", handler.ClearCart)
+	cart.POST("/items", handler.AddItem)
+	cart.PUT("/items/:id", handler.UpdateItem)
+	cart.DELETE("/items/:id", handler.RemoveItem)
+	cart.POST("/checkout", handler.Checkout)
+
+	orders := rg.Group("/redemption-orders", middleware.AuthMiddleware())
+
+	orders.GET("", handler.GetOrders)
+	orders.GET("/:id", handler.GetOrder)
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCatalogRoutes(rg *gin.RouterGroup, handler *handler.CatalogHandler) {
+	catalog := rg.Group("/admin/catalog", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	catalog.POST("/products/import", handler.ImportProducts)
+	catalog.GET("/products/export", handler.ExportProducts)
+	catalog.POST("/categories/import", handler.ImportCategories)
+	catalog.GET("/categories/export", handler.ExportCategories)
+}
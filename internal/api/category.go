@@ -10,8 +10,10 @@ func RegisterCategoryRoutes(rg *gin.RouterGroup, handler *handler.CategoryHandle
 	categories := rg.Group("/categories")
 
 	categories.GET("", handler.GetAllCategories)
+	categories.GET("/tree", handler.GetCategoryTree)
 	categories.GET("/:id/details", handler.GetCategoryDetails)
 	categories.POST("", middleware.AdminMiddleware(), handler.CreateCategory)
 	categories.PUT("/:id", middleware.AdminMiddleware(), handler.UpdateCategory)
 	categories.DELETE("/:id", middleware.AdminMiddleware(), handler.DeleteCategory)
+	categories.POST("/:id/move", middleware.AdminMiddleware(), handler.MoveCategory)
 }
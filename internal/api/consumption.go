@@ -0,0 +1,29 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterConsumptionRoutes(rg *gin.RouterGroup, handler *handler.ConsumptionHandler, auth middleware.APIKeyAuthenticator) {
+	actions := rg.Group("/billable-actions")
+
+	actions.GET("", handler.GetActions)
+	actions.POST("", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.CreateAction)
+	actions.PUT("/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.UpdateAction)
+
+	rg.GET("/wallets/usage", middleware.AuthMiddleware(), handler.GetMyUsage)
+
+	admin := rg.Group("/admin", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	admin.GET("/billable-actions", handler.GetAllActions)
+	admin.GET("/users/:id/usage", handler.GetUserUsage)
+
+	integrations := rg.Group("/integrations", middleware.APIKeyMiddleware(auth, "credits:consume"))
+
+	integrations.POST("/users/:id/consumption", handler.Consume)
+	integrations.POST("/users/:id/credit-holds", handler.Hold)
+	integrations.POST("/credit-holds/:holdId/capture", handler.CaptureHold)
+	integrations.POST("/credit-holds/:holdId/release", handler.ReleaseHold)
+}
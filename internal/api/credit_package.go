@@ -14,4 +14,8 @@ func RegisterCreditPackageRoutes(rg *gin.RouterGroup, handler *handler.CreditPac
 	creditPackages.POST("", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.CreateCreditPackage)
 	creditPackages.PUT("/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.UpdateCreditPackages)
 	creditPackages.DELETE("/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.DeleteCreditPackage)
+	creditPackages.GET("/:id/price-history", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.GetPriceHistory)
+	creditPackages.GET("/:id/price-changes", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.GetPriceChanges)
+	creditPackages.POST("/:id/price-changes", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.SchedulePriceChange)
+	creditPackages.DELETE("/:id/price-changes/:changeId", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.CancelPriceChange)
 }
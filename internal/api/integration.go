@@ -0,0 +1,14 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterIntegrationRoutes(rg *gin.RouterGroup, handler *handler.IntegrationHandler, auth middleware.APIKeyAuthenticator) {
+	integrations := rg.Group("/integrations")
+
+	integrations.GET("/users/:id/wallet", middleware.APIKeyMiddleware(auth, "wallets:read"), handler.GetWallet)
+	integrations.POST("/users/:id/points", middleware.APIKeyMiddleware(auth, "points:award"), handler.AwardPoints)
+}
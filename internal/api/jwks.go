@@ -0,0 +1,10 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterJWKSRoutes(r gin.IRoutes, handler *handler.JWKSHandler) {
+	r.GET("/.well-known/jwks.json", handler.GetJWKS)
+}
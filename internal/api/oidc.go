@@ -0,0 +1,13 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOIDCRoutes(rg *gin.RouterGroup, handler *handler.OIDCHandler) {
+	oidc := rg.Group("/auth/oidc")
+
+	oidc.GET("/:provider/login", handler.StartLogin)
+	oidc.GET("/:provider/callback", handler.Callback)
+}
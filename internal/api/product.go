@@ -11,7 +11,15 @@ func RegisterProductRoutes(rg *gin.RouterGroup, handler *handler.ProductHandler)
 
 	products.GET("", handler.GetAllProducts)
 	products.GET("/search", handler.SearchProducts)
+	products.GET("/archived", middleware.AdminMiddleware(), handler.GetArchivedProducts)
+	products.GET("/:id", handler.GetProductByID)
 	products.POST("", middleware.AdminMiddleware(), handler.CreateProduct)
 	products.PUT("/:id", middleware.AdminMiddleware(), handler.UpdateProduct)
 	products.DELETE("/:id", middleware.AdminMiddleware(), handler.DeleteProduct)
+	products.POST("/:id/restore", middleware.AdminMiddleware(), handler.RestoreProduct)
+	products.POST("/:id/variants", middleware.AdminMiddleware(), handler.CreateVariant)
+	products.PUT("/:id/variants/:variantId", middleware.AdminMiddleware(), handler.UpdateVariant)
+	products.DELETE("/:id/variants/:variantId", middleware.AdminMiddleware(), handler.DeleteVariant)
+	products.POST("/:id/stock/adjustments", middleware.AdminMiddleware(), handler.AdjustStock)
+	products.GET("/:id/stock/movements", middleware.AdminMiddleware(), handler.GetStockMovements)
 }
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterProductImageRoutes(rg *gin.RouterGroup, handler *handler.ProductImageHandler) {
+	images := rg.Group("/products/:id/images")
+
+	images.GET("", handler.ListImages)
+	images.POST("", middleware.AdminMiddleware(), handler.UploadImages)
+	images.PUT("/order", middleware.AdminMiddleware(), handler.ReorderImages)
+	images.PUT("/:imageId/primary", middleware.AdminMiddleware(), handler.SetPrimaryImage)
+	images.DELETE("/:imageId", middleware.AdminMiddleware(), handler.DeleteImage)
+}
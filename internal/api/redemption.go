@@ -11,5 +11,8 @@ func RegisterRedemptionRoutes(rg *gin.RouterGroup, handler *handler.RedemptionHa
 
 	redemption.POST("", middleware.AuthMiddleware(), handler.CreateRedemption)
 	redemption.GET("", middleware.AuthMiddleware(), handler.GetUserRedemptions)
+	redemption.GET("/allowance", middleware.AuthMiddleware(), handler.GetAllowance)
+	redemption.GET("/payment-settings", middleware.AuthMiddleware(), handler.GetPaymentSettings)
+	redemption.PUT("/payment-settings", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.UpdatePaymentSettings)
 	redemption.GET("/:id", middleware.AuthMiddleware(), handler.GetRedemptionByID)
 }
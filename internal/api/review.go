@@ -0,0 +1,20 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterReviewRoutes(rg *gin.RouterGroup, handler *handler.ReviewHandler) {
+	reviews := rg.Group("/products/:id/reviews")
+
+	reviews.GET("", handler.GetProductReviews)
+	reviews.POST("", middleware.AuthMiddleware(), handler.CreateReview)
+	reviews.PUT("/:reviewId", middleware.AuthMiddleware(), handler.UpdateReview)
+
+	admin := rg.Group("/admin/reviews", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	admin.GET("", handler.GetAllReviews)
+	admin.PUT("/:id/status", handler.ModerateReview)
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSubscriptionRoutes(rg *gin.RouterGroup, handler *handler.SubscriptionHandler) {
+	plans := rg.Group("/subscription-plans")
+
+	plans.GET("", handler.GetPlans)
+	plans.POST("", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.CreatePlan)
+	plans.PUT("/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.UpdatePlan)
+
+	subscriptions := rg.Group("/subscriptions", middleware.AuthMiddleware())
+
+	subscriptions.GET("", handler.GetSubscriptions)
+	subscriptions.POST("", handler.Subscribe)
+	subscriptions.PUT("/:id/plan", handler.ChangePlan)
+	subscriptions.POST("/:id/pause", handler.Pause)
+	subscriptions.POST("/:id/resume", handler.Resume)
+	subscriptions.POST("/:id/cancel", handler.Cancel)
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"Start/internal/handler"
+	"Start/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterWishlistRoutes(rg *gin.RouterGroup, handler *handler.WishlistHandler) {
+	wishlist := rg.Group("/wishlist", middleware.AuthMiddleware())
+
+	wishlist.GET("", handler.GetWishlist)
+	wishlist.POST("", handler.AddToWishlist)
+	wishlist.DELETE("/:productId", handler.RemoveFromWishlist)
+
+	notifications := rg.Group("/notifications", middleware.AuthMiddleware())
+
+	notifications.GET("", handler.GetNotifications)
+	notifications.PUT("/:id/read", handler.MarkNotificationRead)
+}
@@ -9,9 +9,9 @@ import (
 	"gorm.io/gorm"
 )
 
-func RegisterAdminModule(rg *gin.RouterGroup, db *gorm.DB) {
+func RegisterAdminModule(rg *gin.RouterGroup, db *gorm.DB, throttle *service.LoginThrottle) {
 	repo := repository.NewRepository(db)
-	svc := service.NewAdminService(repo)
+	svc := service.NewAdminService(repo, throttle)
 	h := handler.NewAdminHandler(svc)
 	api.RegisterAdminRoutes(rg, h)
 }
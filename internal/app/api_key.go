@@ -0,0 +1,20 @@
+package app
+
+import (
+	"Start/internal/api"
+	"Start/internal/handler"
+	"Start/internal/repository"
+	"Start/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterAPIKeyModule(rg *gin.RouterGroup, db *gorm.DB) {
+	repo := repository.NewRepository(db)
+	keySvc := service.NewAPIKeyService(repo)
+	api.RegisterAPIKeyRoutes(rg, handler.NewAPIKeyHandler(keySvc))
+
+	integrationSvc := service.NewIntegrationService(repo)
+	api.RegisterIntegrationRoutes(rg, handler.NewIntegrationHandler(integrationSvc), keySvc)
+}
@@ -10,9 +10,9 @@ import (
 	"gorm.io/gorm"
 )
 
-func RegisterAuthModule(rg *gin.RouterGroup, db *gorm.DB) {
+func RegisterAuthModule(rg *gin.RouterGroup, db *gorm.DB, throttle *service.LoginThrottle) {
 	repo := repository.NewRepository(db)
-	svc := service.NewAuthService(repo)
+	svc := service.NewAuthService(repo, throttle)
 	h := handler.NewAuthHandler(svc)
 	api.RegisterAuthRoutes(rg, h)
 }
@@ -1,21 +1,45 @@
 package app
 
 import (
+	"Start/internal/repository"
+	"Start/internal/service"
+	"Start/internal/shared/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"os"
 )
 
 func RegisterModules(r *gin.Engine, db *gorm.DB) {
 	apiGroup := r.Group("/api")
 
-	RegisterAdminModule(apiGroup, db)
-	RegisterAuthModule(apiGroup, db)
+	RegisterJWKSModule(r)
+
+	throttle := service.NewLoginThrottle(newLoginAttemptStore(db))
+
+	RegisterAdminModule(apiGroup, db, throttle)
+	RegisterAuthModule(apiGroup, db, throttle)
+	RegisterOIDCModule(apiGroup, db, throttle)
 	RegisterUserModule(apiGroup, db)
 	RegisterCategoryModule(apiGroup, db)
 	RegisterCreditPackageModule(apiGroup, db)
 	RegisterProductModule(apiGroup, db)
+	RegisterProductImageModule(r, apiGroup, db, storage.NewBlobStoreFromEnv())
+	RegisterCatalogModule(apiGroup, db)
+	RegisterReviewModule(apiGroup, db)
+	RegisterWishlistModule(apiGroup, db)
 	RegisterPurchaseModule(apiGroup, db)
+	RegisterSubscriptionModule(apiGroup, db)
 	RegisterRedemptionModule(apiGroup, db)
+	RegisterCartModule(apiGroup, db)
 	RegisterWalletModule(apiGroup, db)
+	RegisterConsumptionModule(apiGroup, db)
 	RegisterAIModule(apiGroup, db)
+	RegisterAPIKeyModule(apiGroup, db)
+}
+
+func newLoginAttemptStore(db *gorm.DB) service.LoginAttemptStore {
+	if os.Getenv("LOGIN_THROTTLE_STORE") == "database" {
+		return service.NewDatabaseLoginAttemptStore(repository.NewRepository(db))
+	}
+	return service.NewMemoryLoginAttemptStore()
 }
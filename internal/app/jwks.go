@@ -0,0 +1,14 @@
+package app
+
+import (
+	"Start/internal/api"
+	"Start/internal/handler"
+	"Start/internal/shared/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterJWKSModule(r *gin.Engine) {
+	h := handler.NewJWKSHandler(utils.AccessKeys)
+	api.RegisterJWKSRoutes(r, h)
+}
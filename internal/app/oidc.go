@@ -0,0 +1,19 @@
+package app
+
+import (
+	"Start/internal/api"
+	"Start/internal/handler"
+	"Start/internal/repository"
+	"Start/internal/service"
+	"Start/internal/shared/oidc"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterOIDCModule(rg *gin.RouterGroup, db *gorm.DB, throttle *service.LoginThrottle) {
+	repo := repository.NewRepository(db)
+	svc := service.NewOIDCService(repo, oidc.LoadProvidersFromEnv(), throttle)
+	h := handler.NewOIDCHandler(svc)
+	api.RegisterOIDCRoutes(rg, h)
+}
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"Start/internal/api"
+	"Start/internal/handler"
+	"Start/internal/repository"
+	"Start/internal/service"
+	"Start/internal/shared/storage"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterProductImageModule(r *gin.Engine, rg *gin.RouterGroup, db *gorm.DB, blobs storage.BlobStore) {
+	if local, ok := blobs.(*storage.LocalStore); ok {
+		r.Static(storage.LocalPublicPath, local.Root())
+	}
+
+	repo := repository.NewRepository(db)
+	svc := service.NewProductImageService(repo, blobs)
+	h := handler.NewProductImageHandler(svc)
+	api.RegisterProductImageRoutes(rg, h)
+}
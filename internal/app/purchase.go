@@ -5,6 +5,7 @@ import (
 	"Start/internal/handler"
 	"Start/internal/repository"
 	"Start/internal/service"
+	"Start/internal/shared/payment"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -12,7 +13,7 @@ import (
 
 func RegisterPurchaseModule(rg *gin.RouterGroup, db *gorm.DB) {
 	repo := repository.NewRepository(db)
-	svc := service.NewPurchaseService(repo)
+	svc := service.NewPurchaseService(repo, payment.NewProviderFromEnv())
 	h := handler.NewPurchaseHandler(svc)
 	api.RegisterPurchaseRoutes(rg, h)
 }
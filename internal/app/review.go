@@ -0,0 +1,18 @@
+package app
+
+import (
+	"Start/internal/api"
+	"Start/internal/handler"
+	"Start/internal/repository"
+	"Start/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterReviewModule(rg *gin.RouterGroup, db *gorm.DB) {
+	repo := repository.NewRepository(db)
+	svc := service.NewReviewService(repo)
+	h := handler.NewReviewHandler(svc)
+	api.RegisterReviewRoutes(rg, h)
+}
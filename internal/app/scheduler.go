@@ -0,0 +1,49 @@
+package app
+
+import (
+	"Start/internal/repository"
+	"Start/internal/service"
+	"Start/internal/shared/payment"
+	"Start/internal/shared/utils"
+	"context"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+// StartScheduler runs the periodic jobs in the background until ctx is done:
+// subscription renewals, scheduled credit package price changes and expired
+// credit holds. Every job locks the rows it works on, so several instances can
+// run it side by side.
+func StartScheduler(ctx context.Context, db *gorm.DB) {
+	repo := repository.NewRepository(db)
+	subscriptions := service.NewSubscriptionService(repo, payment.NewProviderFromEnv())
+	consumption := service.NewConsumptionService(repo)
+	interval := time.Duration(utils.EnvInt("SCHEDULER_INTERVAL_SECONDS", 60)) * time.Second
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if n, err := subscriptions.ProcessDueRenewals(time.Now()); err != nil {
+				log.Printf("Subscription renewals failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Processed %d subscription renewals", n)
+			}
+			if err := repo.ApplyDuePriceChanges(); err != nil {
+				log.Printf("Applying price changes failed: %v", err)
+			}
+			if n, err := consumption.ReleaseExpiredHolds(time.Now()); err != nil {
+				log.Printf("Releasing expired credit holds failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Released %d expired credit holds", n)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	"Start/internal/api"
+	"Start/internal/handler"
+	"Start/internal/repository"
+	"Start/internal/service"
+	"Start/internal/shared/payment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterSubscriptionModule(rg *gin.RouterGroup, db *gorm.DB) {
+	repo := repository.NewRepository(db)
+	svc := service.NewSubscriptionService(repo, payment.NewProviderFromEnv())
+	h := handler.NewSubscriptionHandler(svc)
+	api.RegisterSubscriptionRoutes(rg, h)
+}
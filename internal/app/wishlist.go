@@ -0,0 +1,18 @@
+package app
+
+import (
+	"Start/internal/api"
+	"Start/internal/handler"
+	"Start/internal/repository"
+	"Start/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterWishlistModule(rg *gin.RouterGroup, db *gorm.DB) {
+	repo := repository.NewRepository(db)
+	svc := service.NewWishlistService(repo)
+	h := handler.NewWishlistHandler(svc)
+	api.RegisterWishlistRoutes(rg, h)
+}
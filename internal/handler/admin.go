@@ -106,12 +106,20 @@ func (h *AdminHandler) UpdateRedemptionStatus(c *gin.Context) {
 	}
 	id := c.Param("id")
 
-	if err := h.service.UpdateRedemptionStatus(id, req.Status); err != nil {
+	if err := h.service.UpdateRedemptionStatus(c.GetString("userId"), id, req.Status, req.Reason); err != nil {
 		switch err.Error() {
 		case "not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": "Redemption not found"})
 		case "invalid status":
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		case "reason required":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required to reject a redemption"})
+		case "redemption is closed":
+			c.JSON(http.StatusConflict, gin.H{"error": "Closed redemptions cannot change status"})
+		case "invalid transition":
+			c.JSON(http.StatusConflict, gin.H{"error": "The redemption cannot move to this status"})
+		case "claimed by another admin":
+			c.JSON(http.StatusConflict, gin.H{"error": "Redemption is claimed by another admin"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
 		}
@@ -130,11 +138,13 @@ func (h *AdminHandler) ManageUserCredits(c *gin.Context) {
 		return
 	}
 
-	err := h.service.ManageUserCredits(userID, req.Action, req.Amount)
+	err := h.service.ManageUserCredits(c.GetString("userId"), userID, req.Action, req.Amount)
 	if err != nil {
 		switch err.Error() {
 		case "user not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case "insufficient balance":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient credits balance"})
 		case "invalid action":
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credit action"})
 		default:
@@ -155,11 +165,13 @@ func (h *AdminHandler) ManageUserPoints(c *gin.Context) {
 		return
 	}
 
-	err := h.service.ManageUserPoints(userID, req.Action, req.Amount)
+	err := h.service.ManageUserPoints(c.GetString("userId"), userID, req.Action, req.Amount)
 	if err != nil {
 		switch err.Error() {
 		case "user not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case "insufficient balance":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient points balance"})
 		case "invalid action":
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid points action"})
 		default:
@@ -195,3 +207,103 @@ func (h *AdminHandler) ModerateUser(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "User status updated"})
 }
+
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	userID := c.Param("id")
+
+	if err := h.service.UnlockUser(userID); err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked"})
+}
+
+func (h *AdminHandler) GetRedemptionQueue(c *gin.Context) {
+	page := utils.ParseIntQuery(c, c.Query("page"), 1)
+	limit := utils.ParseIntQuery(c, c.Query("limit"), 20)
+	overdue := parseBoolPtr(c.Query("overdue"))
+	filters := types.RedemptionQueueFilters{
+		Status:      c.Query("status"),
+		ProductID:   c.Query("product_id"),
+		CategoryID:  c.Query("category_id"),
+		AssignedTo:  c.Query("assigned_to"),
+		MinAgeHours: parseInt(c.Query("min_age_hours"), 0),
+		MaxAgeHours: parseInt(c.Query("max_age_hours"), 0),
+		Overdue:     overdue != nil && *overdue,
+	}
+
+	items, meta, err := h.service.GetRedemptionQueue(c.GetString("userId"), filters, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch redemption queue"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"redemptions": items, "pagination": meta})
+}
+
+func (h *AdminHandler) ClaimRedemption(c *gin.Context) {
+	item, err := h.service.ClaimRedemption(c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		respondRedemptionQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"redemption": item})
+}
+
+func (h *AdminHandler) AssignRedemption(c *gin.Context) {
+	var req types.AssignRedemptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	item, err := h.service.AssignRedemption(c.GetString("userId"), c.Param("id"), req.AdminID)
+	if err != nil {
+		respondRedemptionQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"redemption": item})
+}
+
+func (h *AdminHandler) BulkUpdateRedemptions(c *gin.Context) {
+	var req types.BulkRedemptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	results, err := h.service.BulkUpdateRedemptions(c.GetString("userId"), req)
+	if err != nil {
+		if err.Error() == "invalid action" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update redemptions"})
+		}
+		return
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results, "succeeded": len(results) - failed, "failed": failed})
+}
+
+func respondRedemptionQueueError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Redemption not found"})
+	case "invalid assignee":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "claimed by another admin", "redemption is closed":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update redemption"})
+	}
+}
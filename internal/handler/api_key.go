@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(service service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req types.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	key, err := h.service.CreateAPIKey(c.GetString("userId"), req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid scope", "invalid ip allowlist":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API key created, store it now as it will not be shown again",
+		"api_key": key,
+	})
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.service.ListAPIKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API keys"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.RevokeAPIKey(id); err != nil {
+		if err.Error() == "api key not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
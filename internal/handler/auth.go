@@ -3,8 +3,11 @@ package handler
 import (
 	"Start/internal/service"
 	"Start/internal/types"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -54,12 +57,24 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.Login(req)
+	res, err := h.service.Login(req, c.ClientIP())
 	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
+	if res.MFARequired {
+		c.JSON(http.StatusOK, gin.H{
+			"message":         "Two-factor authentication required",
+			"mfa_required":    true,
+			"challenge_token": res.ChallengeToken,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":       "Login successful",
 		"access_token":  res.AccessToken,
@@ -109,3 +124,142 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
 }
+
+func (h *AuthHandler) LoginWithMFA(c *gin.Context) {
+	var req types.MFALoginInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	res, err := h.service.CompleteMFALogin(req, c.ClientIP())
+	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid challenge":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		case "invalid code":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authentication code"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"access_token":  res.AccessToken,
+		"refresh_token": res.RefreshToken,
+		"user":          res.User,
+	})
+}
+
+func (h *AuthHandler) EnrollTOTP(c *gin.Context) {
+	userID := c.GetString("userId")
+
+	res, err := h.service.BeginTOTPEnrollment(userID)
+	if err != nil {
+		if err.Error() == "two-factor authentication already enabled" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start enrollment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *AuthHandler) ConfirmTOTP(c *gin.Context) {
+	var req types.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID := c.GetString("userId")
+	res, err := h.service.ConfirmTOTPEnrollment(userID, req.Code)
+	if err != nil {
+		switch err.Error() {
+		case "two-factor authentication already enabled":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "enrollment not started", "invalid code":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable two-factor authentication"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": res.RecoveryCodes,
+		"access_token":   res.Tokens.AccessToken,
+		"refresh_token":  res.Tokens.RefreshToken,
+	})
+}
+
+func (h *AuthHandler) DisableTOTP(c *gin.Context) {
+	var req types.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID := c.GetString("userId")
+	if err := h.service.DisableTOTP(userID, req.Password, req.Code); err != nil {
+		switch err.Error() {
+		case "two-factor authentication not enabled":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "incorrect password", "invalid code":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable two-factor authentication"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req types.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID := c.GetString("userId")
+	codes, err := h.service.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		switch err.Error() {
+		case "two-factor authentication not enabled":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "invalid code":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to regenerate recovery codes"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+func respondThrottled(c *gin.Context, err error) bool {
+	var throttled *service.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+
+	seconds := int(math.Ceil(throttled.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	if throttled.Locked {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Account temporarily locked", "retry_after": seconds})
+	} else {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many login attempts", "retry_after": seconds})
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type CartHandler struct {
+	service service.CartService
+}
+
+func NewCartHandler(service service.CartService) *CartHandler {
+	return &CartHandler{service}
+}
+
+func (h *CartHandler) GetCart(c *gin.Context) {
+	cart, err := h.service.GetCart(c.GetString("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cart"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cart": cart})
+}
+
+func (h *CartHandler) AddItem(c *gin.Context) {
+	var req types.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	cart, err := h.service.AddItem(c.GetString("userId"), req)
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cart": cart})
+}
+
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	var req types.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	cart, err := h.service.UpdateItem(c.GetString("userId"), c.Param("id"), req.Quantity)
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cart": cart})
+}
+
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	cart, err := h.service.RemoveItem(c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cart": cart})
+}
+
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	if err := h.service.ClearCart(c.GetString("userId")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear cart"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Cart cleared"})
+}
+
+func (h *CartHandler) Checkout(c *gin.Context) {
+	order, err := h.service.Checkout(c.GetString("userId"))
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"order": order})
+}
+
+func (h *CartHandler) GetOrders(c *gin.Context) {
+	page := parseInt(c.Query("page"), 1)
+	limit := parseInt(c.Query("limit"), 20)
+
+	orders, meta, err := h.service.GetOrders(c.GetString("userId"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"orders": orders, "pagination": meta})
+}
+
+func (h *CartHandler) GetOrder(c *gin.Context) {
+	order, err := h.service.GetOrder(c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		if err.Error() == "order not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+func respondCartError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "product not found", "variant not found", "cart item not found", "user wallet not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "product is not available for redemption", "variant is required", "invalid quantity", "insufficient points",
+		"cart is empty":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "insufficient stock", "offer quantity cap reached", "cart changed", "redemption limit reached":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "redemption cooldown active":
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const catalogImportMaxBytes = 10 << 20
+
+type CatalogHandler struct {
+	service service.CatalogService
+}
+
+func NewCatalogHandler(service service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{service}
+}
+
+func (h *CatalogHandler) ImportProducts(c *gin.Context) {
+	h.runImport(c, func(format string, body io.Reader, dryRun bool) (*types.ImportResult, error) {
+		return h.service.ImportProducts(c.GetString("userId"), format, body, dryRun)
+	})
+}
+
+func (h *CatalogHandler) ImportCategories(c *gin.Context) {
+	h.runImport(c, h.service.ImportCategories)
+}
+
+func (h *CatalogHandler) ExportProducts(c *gin.Context) {
+	h.runExport(c, "products", h.service.ExportProducts)
+}
+
+func (h *CatalogHandler) ExportCategories(c *gin.Context) {
+	h.runExport(c, "categories", h.service.ExportCategories)
+}
+
+func (h *CatalogHandler) runImport(c *gin.Context, importFn func(string, io.Reader, bool) (*types.ImportResult, error)) {
+	format := c.Query("format")
+	if format == "" {
+		format = "json"
+		if strings.Contains(c.ContentType(), "csv") {
+			format = "csv"
+		}
+	}
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	dryRun := parseBoolPtr(c.Query("dry_run"))
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, catalogImportMaxBytes)
+
+	result, err := importFn(format, body, dryRun != nil && *dryRun)
+	if err != nil {
+		switch {
+		case err.Error() == "invalid JSON", err.Error() == "invalid CSV", strings.HasPrefix(err.Error(), "missing column"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Import failed"})
+		}
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *CatalogHandler) runExport(c *gin.Context, name string, exportFn func(string, io.Writer) error) {
+	format := c.DefaultQuery("format", "csv")
+	switch format {
+	case "csv":
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+	case "json":
+		c.Header("Content-Type", "application/json")
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+name+"."+format)
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure can only cut the stream short.
+	if err := exportFn(format, c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
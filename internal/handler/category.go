@@ -25,7 +25,12 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 
 	resp, err := h.service.CreateCategory(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Creation failed"})
+		switch err.Error() {
+		case "invalid translation":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Creation failed"})
+		}
 		return
 	}
 
@@ -40,7 +45,7 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 	if p := c.Query("parent_id"); p != "" {
 		parentID = &p
 	}
-	categories, err := h.service.GetAllCategories(parentID)
+	categories, err := h.service.GetAllCategories(parentID, requestLocale(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fetch failed"})
 		return
@@ -48,12 +53,23 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"categories": categories})
 }
 
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.service.GetCategoryTree(requestLocale(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fetch failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"categories": tree})
+}
+
 func (h *CategoryHandler) GetCategoryDetails(c *gin.Context) {
 	id := c.Param("id")
 	page := utils.ParseIntQuery(c, "page", 1)
 	limit := utils.ParseIntQuery(c, "limit", 20)
 
-	data, err := h.service.GetCategoryDetails(id, page, limit)
+	includeDescendants := parseBoolPtr(c.Query("include_descendants"))
+
+	data, err := h.service.GetCategoryDetails(id, page, limit, includeDescendants != nil && *includeDescendants, requestLocale(c))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -73,9 +89,14 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 
 	category, err := h.service.UpdateCategory(id, &req)
 	if err != nil {
-		if err.Error() == "category not found" {
+		switch err.Error() {
+		case "category not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
+		case "parent category not found", "invalid translation":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "category cycle":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A category cannot be moved under itself or its descendants"})
+		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "UpdateCreditPackage failed"})
 		}
 		return
@@ -89,9 +110,48 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.service.DeleteCategory(id); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	if err := h.service.DeleteCategory(id, c.Query("strategy"), c.Query("target_id")); err != nil {
+		switch err.Error() {
+		case "category not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "category is not empty":
+			c.JSON(http.StatusConflict, gin.H{"error": "Category has products or subcategories; choose the reassign or archive strategy"})
+		case "invalid delete strategy", "target category not found":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "category cycle":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The target category is inside the deleted category"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
+		}
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
+
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	var req types.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	category, err := h.service.MoveCategory(c.Param("id"), req.ParentCategoryID)
+	if err != nil {
+		switch err.Error() {
+		case "category not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "parent category not found":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "category cycle":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A category cannot be moved under itself or its descendants"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Move failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Category moved successfully",
+		"category": category,
+	})
+}
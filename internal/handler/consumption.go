@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ConsumptionHandler struct {
+	service service.ConsumptionService
+}
+
+func NewConsumptionHandler(service service.ConsumptionService) *ConsumptionHandler {
+	return &ConsumptionHandler{service}
+}
+
+func (h *ConsumptionHandler) GetActions(c *gin.Context) {
+	actions, err := h.service.GetActions(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch actions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"actions": actions})
+}
+
+func (h *ConsumptionHandler) GetAllActions(c *gin.Context) {
+	actions, err := h.service.GetActions(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch actions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"actions": actions})
+}
+
+func (h *ConsumptionHandler) CreateAction(c *gin.Context) {
+	var req types.CreateBillableActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+
+	action, err := h.service.CreateAction(&req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid action key":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "action already exists":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create action"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"action": action})
+}
+
+func (h *ConsumptionHandler) UpdateAction(c *gin.Context) {
+	var req types.UpdateBillableActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+
+	action, err := h.service.UpdateAction(c.Param("id"), &req)
+	if err != nil {
+		switch err.Error() {
+		case "action not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "invalid action":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update action"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"action": action})
+}
+
+func (h *ConsumptionHandler) Consume(c *gin.Context) {
+	var req types.ConsumeCreditsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	usage, err := h.service.Consume(c.GetString("apiKeyId"), c.Param("id"), &req)
+	if err != nil {
+		respondConsumptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"usage": usage})
+}
+
+func (h *ConsumptionHandler) Hold(c *gin.Context) {
+	var req types.CreditHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	hold, err := h.service.Hold(c.GetString("apiKeyId"), c.Param("id"), &req)
+	if err != nil {
+		respondConsumptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"hold": hold})
+}
+
+func (h *ConsumptionHandler) CaptureHold(c *gin.Context) {
+	var req types.CaptureHoldRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+
+	hold, err := h.service.CaptureHold(c.GetString("apiKeyId"), c.Param("holdId"), req.Quantity)
+	if err != nil {
+		respondConsumptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"hold": hold})
+}
+
+func (h *ConsumptionHandler) ReleaseHold(c *gin.Context) {
+	hold, err := h.service.ReleaseHold(c.GetString("apiKeyId"), c.Param("holdId"))
+	if err != nil {
+		respondConsumptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"hold": hold})
+}
+
+func (h *ConsumptionHandler) GetMyUsage(c *gin.Context) {
+	h.getUsage(c, c.GetString("userId"))
+}
+
+func (h *ConsumptionHandler) GetUserUsage(c *gin.Context) {
+	h.getUsage(c, c.Param("id"))
+}
+
+func (h *ConsumptionHandler) getUsage(c *gin.Context, userID string) {
+	report, err := h.service.GetUsageReport(userID, c.Query("dateFrom"), c.Query("dateTo"))
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch usage"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"usage": report})
+}
+
+func respondConsumptionError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case "action not found", "hold not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid quantity", "invalid ttl":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "insufficient credits":
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+	case "duplicate reference":
+		c.JSON(http.StatusConflict, gin.H{"error": "Reference already processed"})
+	case "hold already settled", "hold expired":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -26,7 +26,7 @@ func (h *CreditPackageHandler) GetAllCreditPackages(c *gin.Context) {
 		activeFilter = &val
 	}
 
-	pkgs, pagination, err := h.service.GetAllCreditPackages(page, limit, activeFilter)
+	pkgs, pagination, err := h.service.GetAllCreditPackages(page, limit, activeFilter, requestLocale(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch packages"})
 		return
@@ -40,7 +40,7 @@ func (h *CreditPackageHandler) GetAllCreditPackages(c *gin.Context) {
 
 func (h *CreditPackageHandler) GetCreditPackageByID(c *gin.Context) {
 	id := c.Param("id")
-	pkg, err := h.service.GetCreditPackageByID(id)
+	pkg, err := h.service.GetCreditPackageByID(id, requestLocale(c))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "CreditPackage not found"})
 		return
@@ -55,8 +55,12 @@ func (h *CreditPackageHandler) CreateCreditPackage(c *gin.Context) {
 		return
 	}
 
-	pkg, err := h.service.CreateCreditPackage(req)
+	pkg, err := h.service.CreateCreditPackage(c.GetString("userId"), req)
 	if err != nil {
+		if err.Error() == "invalid translation" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create package"})
 		return
 	}
@@ -75,8 +79,12 @@ func (h *CreditPackageHandler) UpdateCreditPackages(c *gin.Context) {
 		return
 	}
 
-	pkg, err := h.service.UpdateCreditPackages(id, req)
+	pkg, err := h.service.UpdateCreditPackages(c.GetString("userId"), id, req)
 	if err != nil {
+		if err.Error() == "invalid translation" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -95,3 +103,63 @@ func (h *CreditPackageHandler) DeleteCreditPackage(c *gin.Context) {
 	}
 	c.Status(http.StatusNoContent)
 }
+
+func (h *CreditPackageHandler) GetPriceHistory(c *gin.Context) {
+	history, err := h.service.GetPriceHistory(c.Param("id"))
+	if err != nil {
+		if err.Error() == "package not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "CreditPackage not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch price history"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+func (h *CreditPackageHandler) GetPriceChanges(c *gin.Context) {
+	changes, err := h.service.GetPriceChanges(c.Param("id"), c.Query("status"))
+	if err != nil {
+		if err.Error() == "package not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "CreditPackage not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch price changes"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"priceChanges": changes})
+}
+
+func (h *CreditPackageHandler) SchedulePriceChange(c *gin.Context) {
+	var req types.ScheduledPriceChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+
+	change, err := h.service.SchedulePriceChange(c.GetString("userId"), c.Param("id"), &req)
+	if err != nil {
+		switch err.Error() {
+		case "package not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "CreditPackage not found"})
+		case "invalid pricing", "effective time must be in the future":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule price change"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"priceChange": change})
+}
+
+func (h *CreditPackageHandler) CancelPriceChange(c *gin.Context) {
+	if err := h.service.CancelPriceChange(c.Param("id"), c.Param("changeId")); err != nil {
+		if err.Error() == "price change not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pending price change not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel price change"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type IntegrationHandler struct {
+	service service.IntegrationService
+}
+
+func NewIntegrationHandler(service service.IntegrationService) *IntegrationHandler {
+	return &IntegrationHandler{service}
+}
+
+func (h *IntegrationHandler) AwardPoints(c *gin.Context) {
+	var req types.AwardPointsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	wallet, err := h.service.AwardPoints(c.GetString("apiKeyId"), c.Param("id"), req)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case "duplicate reference":
+			c.JSON(http.StatusConflict, gin.H{"error": "Reference already processed"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to award points"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Points awarded", "wallet": wallet})
+}
+
+func (h *IntegrationHandler) GetWallet(c *gin.Context) {
+	wallet, err := h.service.GetWallet(c.Param("id"))
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wallet"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"wallet": wallet})
+}
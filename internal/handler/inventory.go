@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+)
+
+func (h *ProductHandler) AdjustStock(c *gin.Context) {
+	var req types.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	movement, err := h.service.AdjustStock(c.GetString("userId"), c.Param("id"), &req)
+	if err != nil {
+		switch err.Error() {
+		case "product not found", "variant not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "invalid stock adjustment", "note is required for corrections", "variant is required":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "insufficient stock":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Stock adjustment failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Stock adjusted successfully",
+		"movement": movement,
+	})
+}
+
+func (h *ProductHandler) GetStockMovements(c *gin.Context) {
+	page := parseInt(c.Query("page"), 1)
+	limit := parseInt(c.Query("limit"), 20)
+
+	movements, meta, err := h.service.GetStockMovements(c.Param("id"), page, limit)
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock movements"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"movements": movements, "pagination": meta})
+}
+
+func (h *AdminHandler) GetStockForecast(c *gin.Context) {
+	windowDays := parseInt(c.Query("window_days"), 30)
+	horizonDays := parseInt(c.Query("horizon_days"), 30)
+
+	items, err := h.service.GetStockForecast(windowDays, horizonDays)
+	if err != nil {
+		if err.Error() == "invalid forecast window" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build stock forecast"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"windowDays":  windowDays,
+		"horizonDays": horizonDays,
+		"products":    items,
+	})
+}
+
+func (h *AdminHandler) GetWishlistedOutOfStock(c *gin.Context) {
+	limit := parseInt(c.Query("limit"), 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	items, err := h.service.GetWishlistedOutOfStock(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wishlist report"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"products": items})
+}
+
+func (h *AdminHandler) GetNotifications(c *gin.Context) {
+	page := parseInt(c.Query("page"), 1)
+	limit := parseInt(c.Query("limit"), 20)
+	unread := parseBoolPtr(c.Query("unread"))
+
+	notifications, total, err := h.service.GetNotifications(unread != nil && *unread, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"pagination": gin.H{
+			"currentPage":  page,
+			"itemsPerPage": limit,
+			"totalItems":   total,
+			"totalPages":   int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+func (h *AdminHandler) MarkNotificationRead(c *gin.Context) {
+	if err := h.service.MarkNotificationRead(c.Param("id")); err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
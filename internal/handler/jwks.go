@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"Start/internal/shared/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type JWKSHandler struct {
+	keys *utils.KeySet
+}
+
+func NewJWKSHandler(keys *utils.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys}
+}
+
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
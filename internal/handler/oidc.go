@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type OIDCHandler struct {
+	service service.OIDCService
+}
+
+func NewOIDCHandler(service service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{service}
+}
+
+func (h *OIDCHandler) StartLogin(c *gin.Context) {
+	authURL, err := h.service.StartLogin(c.Param("provider"))
+	if err != nil {
+		if err.Error() == "unknown provider" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown identity provider"})
+		} else {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Identity provider unavailable"})
+		}
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if errCode := c.Query("error"); errCode != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Sign-in was not completed", "reason": errCode})
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code and state are required"})
+		return
+	}
+
+	res, err := h.service.CompleteLogin(c.Param("provider"), code, state)
+	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "unknown provider":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown identity provider"})
+		case "invalid state":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login state"})
+		case "authentication failed":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication with identity provider failed"})
+		case "email not verified":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Identity provider did not return a verified email"})
+		case "account suspended":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is suspended"})
+		case "account banned":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is banned"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	if res.MFARequired {
+		c.JSON(http.StatusOK, gin.H{
+			"message":         "Two-factor authentication required",
+			"mfa_required":    true,
+			"challenge_token": res.ChallengeToken,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"access_token":  res.AccessToken,
+		"refresh_token": res.RefreshToken,
+		"user":          res.User,
+	})
+}
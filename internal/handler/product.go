@@ -5,6 +5,7 @@ import (
 	"Start/internal/types"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strings"
 )
 
 type ProductHandler struct {
@@ -21,14 +22,27 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	sortBy := c.Query("sort_by")
 	sortOrder := c.Query("sort_order")
 
+	filters := parseProductFilters(c)
+
+	products, meta, err := h.service.GetAllProducts(filters, page, limit, sortBy, sortOrder, requestLocale(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"products": products, "pagination": meta})
+}
+
+func (h *ProductHandler) GetArchivedProducts(c *gin.Context) {
+	page := parseInt(c.Query("page"), 1)
+	limit := parseInt(c.Query("limit"), 20)
+
 	filters := types.ProductFilters{
-		CategoryID: c.Query("category_id"),
-		IsOffer:    parseBoolPtr(c.Query("is_offer")),
-		MinPoints:  parseInt(c.Query("min_points"), 0),
-		MaxPoints:  parseInt(c.Query("max_points"), 0),
+		CategoryIDs:        parseList(c.QueryArray("category_id")),
+		Archived:           true,
+		IncludeDescendants: strings.EqualFold(c.Query("include_descendants"), "true"),
 	}
 
-	products, meta, err := h.service.GetAllProducts(filters, page, limit, sortBy, sortOrder)
+	products, meta, err := h.service.GetAllProducts(filters, page, limit, c.Query("sort_by"), c.Query("sort_order"), requestLocale(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
 		return
@@ -36,6 +50,19 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"products": products, "pagination": meta})
 }
 
+func (h *ProductHandler) GetProductByID(c *gin.Context) {
+	product, err := h.service.GetProductByID(c.Param("id"), requestLocale(c))
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch product"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"product": product})
+}
+
 func (h *ProductHandler) SearchProducts(c *gin.Context) {
 	query := c.Query("query")
 	if query == "" {
@@ -45,20 +72,36 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 	page := parseInt(c.Query("page"), 1)
 	limit := parseInt(c.Query("limit"), 20)
 
-	filters := types.ProductFilters{
-		CategoryID: c.Query("category_id"),
-		IsOffer:    parseBoolPtr(c.Query("is_offer")),
-		MinPoints:  parseInt(c.Query("min_points"), 0),
-		MaxPoints:  parseInt(c.Query("max_points"), 0),
+	filters := parseProductFilters(c)
+
+	lang := c.Query("lang")
+	locale := requestLocale(c)
+	products, meta, err := h.service.SearchProducts(query, lang, locale, filters, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
 	}
 
-	products, meta, err := h.service.SearchProducts(query, filters, page, limit)
+	facets, err := h.service.SearchProductFacets(query, lang, locale, filters)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"products": products, "pagination": meta})
+	c.JSON(http.StatusOK, gin.H{"products": products, "facets": facets, "pagination": meta})
+}
+
+func parseProductFilters(c *gin.Context) types.ProductFilters {
+	return types.ProductFilters{
+		CategoryIDs: parseList(c.QueryArray("category_id")),
+		Tags:        parseList(c.QueryArray("tag")),
+		IsOffer:     parseBoolPtr(c.Query("is_offer")),
+		InStock:     parseBoolPtr(c.Query("in_stock")),
+		MinPoints:   parseInt(c.Query("min_points"), 0),
+		MaxPoints:   parseInt(c.Query("max_points"), 0),
+
+		IncludeDescendants: strings.EqualFold(c.Query("include_descendants"), "true"),
+	}
 }
 
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
@@ -68,9 +111,16 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.service.CreateProduct(&req)
+	product, err := h.service.CreateProduct(c.GetString("userId"), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Creation failed"})
+		switch err.Error() {
+		case "invalid category", "invalid offer window", "invalid offer points", "invalid offer quantity cap",
+			"invalid stock quantity", "invalid low stock threshold", "invalid translation", "invalid credit payment rule",
+			"invalid redemption limits":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Creation failed"})
+		}
 		return
 	}
 
@@ -88,11 +138,18 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.service.UpdateProduct(id, &req)
+	product, err := h.service.UpdateProduct(c.GetString("userId"), id, &req)
 	if err != nil {
-		if err.Error() == "product not found" {
+		switch err.Error() {
+		case "product not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
+		case "invalid category ID", "invalid offer window", "invalid offer points", "invalid offer quantity cap",
+			"invalid stock quantity", "invalid low stock threshold", "stock is managed by variants", "invalid translation",
+			"invalid credit payment rule", "invalid redemption limits":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "insufficient stock":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "UpdateCreditPackage failed"})
 		}
 		return
@@ -112,3 +169,20 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	}
 	c.Status(http.StatusNoContent)
 }
+
+func (h *ProductHandler) RestoreProduct(c *gin.Context) {
+	product, err := h.service.RestoreProduct(c.Param("id"))
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Restore failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Product restored successfully",
+		"product": product,
+	})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/shared/utils"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+)
+
+type ProductImageHandler struct {
+	service service.ProductImageService
+}
+
+func NewProductImageHandler(service service.ProductImageService) *ProductImageHandler {
+	return &ProductImageHandler{service}
+}
+
+func (h *ProductImageHandler) UploadImages(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multipart form"})
+		return
+	}
+
+	var uploads []types.ImageUpload
+	for _, fh := range form.File["images"] {
+		if fh.Size > utils.MaxImageUploadBytes {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "image too large"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(f, utils.MaxImageUploadBytes+1))
+		f.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+			return
+		}
+		uploads = append(uploads, types.ImageUpload{Filename: fh.Filename, Data: data})
+	}
+
+	primary := parseBoolPtr(c.PostForm("primary"))
+	images, err := h.service.UploadImages(c.Param("id"), uploads, primary != nil && *primary)
+	if err != nil {
+		respondProductImageError(c, err, "Upload failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"images": images})
+}
+
+func (h *ProductImageHandler) ListImages(c *gin.Context) {
+	images, err := h.service.ListImages(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch images"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"images": images})
+}
+
+func (h *ProductImageHandler) ReorderImages(c *gin.Context) {
+	var req types.ReorderProductImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	images, err := h.service.ReorderImages(c.Param("id"), req.ImageIDs)
+	if err != nil {
+		respondProductImageError(c, err, "Reorder failed")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"images": images})
+}
+
+func (h *ProductImageHandler) SetPrimaryImage(c *gin.Context) {
+	images, err := h.service.SetPrimaryImage(c.Param("id"), c.Param("imageId"))
+	if err != nil {
+		respondProductImageError(c, err, "Update failed")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"images": images})
+}
+
+func (h *ProductImageHandler) DeleteImage(c *gin.Context) {
+	if err := h.service.DeleteImage(c.Param("id"), c.Param("imageId")); err != nil {
+		respondProductImageError(c, err, "Delete failed")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func respondProductImageError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "product not found", "image not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "image too large":
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case "unsupported image type":
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case "no images uploaded", "too many images", "invalid image", "image dimensions too large", "invalid image order":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (h *ProductHandler) CreateVariant(c *gin.Context) {
+	var req types.CreateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	variant, err := h.service.CreateVariant(c.GetString("userId"), c.Param("id"), &req)
+	if err != nil {
+		respondVariantError(c, err, "Creation failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Variant created successfully",
+		"variant": variant,
+	})
+}
+
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	var req types.UpdateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	variant, err := h.service.UpdateVariant(c.GetString("userId"), c.Param("id"), c.Param("variantId"), &req)
+	if err != nil {
+		respondVariantError(c, err, "Update failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Variant updated successfully",
+		"variant": variant,
+	})
+}
+
+func (h *ProductHandler) DeleteVariant(c *gin.Context) {
+	if err := h.service.DeleteVariant(c.Param("id"), c.Param("variantId")); err != nil {
+		respondVariantError(c, err, "Delete failed")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func respondVariantError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "product not found", "variant not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "sku already exists", "insufficient stock":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "sku and options are required", "invalid variant":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -28,12 +28,16 @@ func (h *RedemptionHandler) CreateRedemption(c *gin.Context) {
 	resp, err := h.service.CreateRedemption(userID, req)
 	if err != nil {
 		switch err.Error() {
-		case "product not found", "user wallet not found":
+		case "product not found", "variant not found", "user wallet not found":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case "product is not available for redemption", "insufficient points", "invalid quantity":
+		case "product is not available for redemption", "variant is required", "insufficient points", "invalid quantity",
+			"insufficient credits", "insufficient balance", "invalid payment split", "credit payment not allowed",
+			"credit share exceeds limit":
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case "insufficient stock":
+		case "insufficient stock", "offer quantity cap reached", "redemption limit reached":
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "redemption cooldown active":
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Redemption failed"})
 		}
@@ -84,3 +88,44 @@ func (h *RedemptionHandler) GetRedemptionByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"redemption": r})
 }
+
+func (h *RedemptionHandler) GetAllowance(c *gin.Context) {
+	allowance, err := h.service.GetAllowance(c.GetString("userId"), c.Query("product_id"))
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch allowance"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"allowance": allowance})
+}
+
+func (h *RedemptionHandler) GetPaymentSettings(c *gin.Context) {
+	settings, err := h.service.GetPaymentSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payment settings"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+func (h *RedemptionHandler) UpdatePaymentSettings(c *gin.Context) {
+	var req types.RedemptionPaymentSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	settings, err := h.service.UpdatePaymentSettings(c.GetString("userId"), req)
+	if err != nil {
+		if err.Error() == "invalid conversion rate" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payment settings"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
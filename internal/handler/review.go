@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ReviewHandler struct {
+	service service.ReviewService
+}
+
+func NewReviewHandler(service service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{service}
+}
+
+func (h *ReviewHandler) GetProductReviews(c *gin.Context) {
+	page := parseInt(c.Query("page"), 1)
+	limit := parseInt(c.Query("limit"), 20)
+
+	reviews, meta, err := h.service.GetProductReviews(c.Param("id"), page, limit)
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews, "pagination": meta})
+}
+
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	var req types.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	review, err := h.service.CreateReview(c.GetString("userId"), c.Param("id"), req)
+	if err != nil {
+		respondReviewError(c, err, "Failed to create review")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Review created successfully",
+		"review":  review,
+	})
+}
+
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	var req types.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	review, err := h.service.UpdateReview(c.GetString("userId"), c.Param("id"), c.Param("reviewId"), req)
+	if err != nil {
+		respondReviewError(c, err, "Failed to update review")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Review updated successfully",
+		"review":  review,
+	})
+}
+
+func (h *ReviewHandler) GetAllReviews(c *gin.Context) {
+	page := parseInt(c.Query("page"), 1)
+	limit := parseInt(c.Query("limit"), 20)
+
+	reviews, meta, err := h.service.GetAllReviews(c.Query("product_id"), c.Query("status"), page, limit)
+	if err != nil {
+		respondReviewError(c, err, "Failed to fetch reviews")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews, "pagination": meta})
+}
+
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	var req types.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	review, err := h.service.ModerateReview(c.GetString("userId"), c.Param("id"), req)
+	if err != nil {
+		respondReviewError(c, err, "Failed to moderate review")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Review status updated",
+		"review":  review,
+	})
+}
+
+func respondReviewError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "product not found", "review not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid rating", "review is too long", "invalid status":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "review requires a delivered redemption", "unauthorized":
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case "already reviewed":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
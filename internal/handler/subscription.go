@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type SubscriptionHandler struct {
+	service service.SubscriptionService
+}
+
+func NewSubscriptionHandler(service service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{service}
+}
+
+func (h *SubscriptionHandler) GetPlans(c *gin.Context) {
+	plans, err := h.service.GetPlans()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plans"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"plans": plans})
+}
+
+func (h *SubscriptionHandler) CreatePlan(c *gin.Context) {
+	var req types.SubscriptionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+
+	plan, err := h.service.CreatePlan(&req)
+	if err != nil {
+		if err.Error() == "invalid plan" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create plan"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"plan": plan})
+}
+
+func (h *SubscriptionHandler) UpdatePlan(c *gin.Context) {
+	var req types.SubscriptionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+
+	plan, err := h.service.UpdatePlan(c.Param("id"), &req)
+	if err != nil {
+		switch err.Error() {
+		case "plan not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "invalid plan":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plan"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"plan": plan})
+}
+
+func (h *SubscriptionHandler) GetSubscriptions(c *gin.Context) {
+	subs, err := h.service.GetSubscriptions(c.GetString("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscriptions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subs})
+}
+
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	var req types.SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+
+	sub, err := h.service.Subscribe(c.GetString("userId"), &req)
+	if err != nil {
+		respondSubscriptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"subscription": sub})
+}
+
+func (h *SubscriptionHandler) ChangePlan(c *gin.Context) {
+	var req types.ChangePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+
+	sub, err := h.service.ChangePlan(c.GetString("userId"), c.Param("id"), req.PlanID)
+	if err != nil {
+		respondSubscriptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscription": sub})
+}
+
+func (h *SubscriptionHandler) Pause(c *gin.Context) {
+	sub, err := h.service.Pause(c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		respondSubscriptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscription": sub})
+}
+
+func (h *SubscriptionHandler) Resume(c *gin.Context) {
+	sub, err := h.service.Resume(c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		respondSubscriptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscription": sub})
+}
+
+func (h *SubscriptionHandler) Cancel(c *gin.Context) {
+	immediately := parseBoolPtr(c.Query("immediately"))
+	sub, err := h.service.Cancel(c.GetString("userId"), c.Param("id"), immediately != nil && *immediately)
+	if err != nil {
+		respondSubscriptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscription": sub})
+}
+
+func respondSubscriptionError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "plan not found", "subscription not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "already subscribed", "invalid subscription state", "payment in progress":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "already on this plan":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "payment failed":
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
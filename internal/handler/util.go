@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"Start/internal/shared/utils"
+	"github.com/gin-gonic/gin"
 	"strconv"
 	"strings"
 )
@@ -13,6 +15,18 @@ func parseInt(val string, def int) int {
 	return i
 }
 
+func parseList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
 func parseBoolPtr(val string) *bool {
 	if strings.ToLower(val) == "true" {
 		b := true
@@ -23,3 +37,12 @@ func parseBoolPtr(val string) *bool {
 	}
 	return nil
 }
+
+// requestLocale negotiates the response language and announces it, so caches
+// keep the translations of a resource apart.
+func requestLocale(c *gin.Context) string {
+	locale := utils.NegotiateLocale(c.Query("lang"), c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", locale)
+	c.Header("Vary", "Accept-Language")
+	return locale
+}
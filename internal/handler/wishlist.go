@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"Start/internal/service"
+	"Start/internal/types"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type WishlistHandler struct {
+	service service.WishlistService
+}
+
+func NewWishlistHandler(service service.WishlistService) *WishlistHandler {
+	return &WishlistHandler{service}
+}
+
+func (h *WishlistHandler) GetWishlist(c *gin.Context) {
+	page := parseInt(c.Query("page"), 1)
+	limit := parseInt(c.Query("limit"), 20)
+
+	items, meta, err := h.service.GetWishlist(c.GetString("userId"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wishlist"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "pagination": meta})
+}
+
+func (h *WishlistHandler) AddToWishlist(c *gin.Context) {
+	var req types.AddWishlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.ProductID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.service.AddToWishlist(c.GetString("userId"), req.ProductID); err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wishlist"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Product added to wishlist"})
+}
+
+func (h *WishlistHandler) RemoveFromWishlist(c *gin.Context) {
+	if err := h.service.RemoveFromWishlist(c.GetString("userId"), c.Param("productId")); err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product is not in the wishlist"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wishlist"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WishlistHandler) GetNotifications(c *gin.Context) {
+	page := parseInt(c.Query("page"), 1)
+	limit := parseInt(c.Query("limit"), 20)
+	unread := parseBoolPtr(c.Query("unread"))
+
+	notifications, meta, err := h.service.GetNotifications(c.GetString("userId"), unread != nil && *unread, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "pagination": meta})
+}
+
+func (h *WishlistHandler) MarkNotificationRead(c *gin.Context) {
+	if err := h.service.MarkNotificationRead(c.GetString("userId"), c.Param("id")); err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
@@ -17,12 +17,47 @@ func AutoMigrate(db *gorm.DB) error {
 		&store.CreditPackage{},
 		&store.Purchase{},
 		&store.Redemption{},
+		&store.RecoveryCode{},
+		&store.LoginAttempt{},
+		&store.APIKey{},
+		&store.WalletTransaction{},
+		&store.UserIdentity{},
+		&store.OIDCLoginState{},
+		&store.ProductVariant{},
+		&store.ProductImage{},
+		&store.InventoryMovement{},
+		&store.AdminNotification{},
+		&store.ProductReview{},
+		&store.WishlistItem{},
+		&store.Notification{},
+		&store.CreditPackagePrice{},
+		&store.CreditPackagePriceChange{},
+		&store.SubscriptionPlan{},
+		&store.Subscription{},
+		&store.BillableAction{},
+		&store.CreditHold{},
+		&store.CreditUsage{},
+		&store.Setting{},
+		&store.RedemptionOrder{},
+		&store.CartItem{},
 	)
 	if err != nil {
 		log.Printf("Migration failed: %v", err)
 		return err
 	}
 
+	if err := migrateProductSearch(db); err != nil {
+		return err
+	}
+
+	if err := migrateCreditPackagePricing(db); err != nil {
+		return err
+	}
+
+	if err := migrateReferenceIndexes(db); err != nil {
+		return err
+	}
+
 	log.Println("Auto-migration completed successfully.")
 	return nil
 }
@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+	"log"
+)
+
+// Packages created before price history was kept get an opening history row,
+// and their old purchases are stamped with that price, the best record left.
+var creditPackagePricingStatements = []string{
+	`INSERT INTO credit_package_price (id, credit_package_id, price_egp, credits, reward_points, effective_from)
+	SELECT gen_random_uuid()::text, cp.id, cp.price_egp, cp.credits, cp.reward_points, cp.created_at
+	FROM credit_package cp
+	WHERE NOT EXISTS (SELECT 1 FROM credit_package_price h WHERE h.credit_package_id = cp.id)`,
+
+	`UPDATE purchase p
+	SET price_egp = h.price_egp, reward_points = h.reward_points, credit_package_price_id = h.id
+	FROM credit_package_price h
+	WHERE p.credit_package_price_id IS NULL
+		AND h.credit_package_id = p.credit_package_id AND h.effective_to IS NULL`,
+}
+
+func migrateCreditPackagePricing(db *gorm.DB) error {
+	for _, stmt := range creditPackagePricingStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			log.Printf("Credit package pricing migration failed: %v", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+	"log"
+)
+
+// Callers that pass their own reference get it recorded once per API key;
+// the unique indexes turn a retry that races the first call into a conflict.
+// Consumption entries reuse hold IDs as references, so only awards are
+// covered in the wallet ledger. Usages created by capturing a hold carry the
+// hold's reference and are left out.
+var referenceIndexStatements = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_transaction_award_reference
+	ON wallet_transaction (api_key_id, reference_id)
+	WHERE reason = 'integration_award' AND reference_id <> ''`,
+
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_credit_hold_reference
+	ON credit_hold (api_key_id, reference)
+	WHERE reference <> ''`,
+
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_credit_usage_reference
+	ON credit_usage (api_key_id, reference)
+	WHERE hold_id IS NULL AND reference <> ''`,
+}
+
+func migrateReferenceIndexes(db *gorm.DB) error {
+	for _, stmt := range referenceIndexStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			log.Printf("Reference index migration failed: %v", err)
+			return err
+		}
+	}
+	return nil
+}
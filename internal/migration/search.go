@@ -0,0 +1,95 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+	"log"
+)
+
+// The search document includes the category name, which a generated column
+// cannot reference, so it is maintained by triggers on product and category.
+// Names and descriptions are indexed together with all of their translations.
+var productSearchStatements = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+
+	`ALTER TABLE product ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+
+	`CREATE OR REPLACE FUNCTION product_search_document(p_name text, p_description text, p_tags jsonb, p_category text)
+RETURNS tsvector LANGUAGE sql IMMUTABLE AS $$
+	SELECT
+		setweight(to_tsvector('english', coalesce(p_name, '')), 'A') ||
+		setweight(to_tsvector('arabic', coalesce(p_name, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(
+			CASE WHEN jsonb_typeof(p_tags) = 'array' THEN
+				(SELECT string_agg(tag, ' ') FROM jsonb_array_elements_text(p_tags) AS tag)
+			END, '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(p_category, '')), 'B') ||
+		setweight(to_tsvector('arabic', coalesce(p_category, '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(p_description, '')), 'C') ||
+		setweight(to_tsvector('arabic', coalesce(p_description, '')), 'C')
+$$`,
+
+	`CREATE OR REPLACE FUNCTION translated_text(p_base text, p_translations jsonb, p_field text)
+RETURNS text LANGUAGE sql IMMUTABLE AS $$
+	SELECT concat_ws(' ', p_base, CASE WHEN jsonb_typeof(p_translations) = 'object' THEN
+		(SELECT string_agg(t.value->>p_field, ' ') FROM jsonb_each(p_translations) AS t)
+	END)
+$$`,
+
+	`CREATE OR REPLACE FUNCTION product_search_vector_refresh() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	NEW.search_vector := product_search_document(
+		translated_text(NEW.name, NEW.translations::jsonb, 'name'),
+		translated_text(NEW.description, NEW.translations::jsonb, 'description'),
+		NEW.tags::jsonb,
+		(SELECT translated_text(c.name, c.translations::jsonb, 'name') FROM category c WHERE c.id = NEW.category_id));
+	RETURN NEW;
+END
+$$`,
+
+	`DROP TRIGGER IF EXISTS product_search_vector_refresh ON product`,
+	`CREATE TRIGGER product_search_vector_refresh
+	BEFORE INSERT OR UPDATE OF name, description, tags, category_id, translations ON product
+	FOR EACH ROW EXECUTE FUNCTION product_search_vector_refresh()`,
+
+	`CREATE OR REPLACE FUNCTION category_search_vector_refresh() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	UPDATE product p
+	SET search_vector = product_search_document(
+		translated_text(p.name, p.translations::jsonb, 'name'),
+		translated_text(p.description, p.translations::jsonb, 'description'),
+		p.tags::jsonb,
+		translated_text(NEW.name, NEW.translations::jsonb, 'name'))
+	WHERE p.category_id = NEW.id;
+	RETURN NULL;
+END
+$$`,
+
+	`DROP TRIGGER IF EXISTS category_search_vector_refresh ON category`,
+	`CREATE TRIGGER category_search_vector_refresh
+	AFTER UPDATE OF name, translations ON category
+	FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name OR OLD.translations::jsonb IS DISTINCT FROM NEW.translations::jsonb)
+	EXECUTE FUNCTION category_search_vector_refresh()`,
+
+	`UPDATE product p
+	SET search_vector = product_search_document(
+		translated_text(p.name, p.translations::jsonb, 'name'),
+		translated_text(p.description, p.translations::jsonb, 'description'),
+		p.tags::jsonb,
+		(SELECT translated_text(c.name, c.translations::jsonb, 'name') FROM category c WHERE c.id = p.category_id))
+	WHERE p.search_vector IS NULL`,
+
+	`CREATE INDEX IF NOT EXISTS idx_product_search_vector ON product USING GIN (search_vector)`,
+	`DROP INDEX IF EXISTS idx_product_name_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_product_names_trgm ON product
+	USING GIN (translated_text(name, translations::jsonb, 'name') gin_trgm_ops)`,
+}
+
+func migrateProductSearch(db *gorm.DB) error {
+	for _, stmt := range productSearchStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			log.Printf("Product search migration failed: %v", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+func (r *Repository) CreateAPIKey(k *store.APIKey) error {
+	return r.db.Create(k).Error
+}
+
+func (r *Repository) ListAPIKeys() ([]store.APIKey, error) {
+	var keys []store.APIKey
+	err := r.db.Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *Repository) FindAPIKeyByID(id string) (*store.APIKey, error) {
+	var k store.APIKey
+	err := r.db.First(&k, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &k, err
+}
+
+func (r *Repository) FindAPIKeyByPrefix(prefix string) (*store.APIKey, error) {
+	var k store.APIKey
+	err := r.db.First(&k, "prefix = ?", prefix).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &k, err
+}
+
+func (r *Repository) RevokeAPIKey(id string) error {
+	return r.db.Model(&store.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *Repository) TouchAPIKey(id, ip string) error {
+	return r.db.Model(&store.APIKey{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": time.Now(), "last_used_ip": ip}).Error
+}
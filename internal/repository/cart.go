@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) ListCartItems(userID string) ([]store.CartItem, error) {
+	var items []store.CartItem
+	err := r.db.Preload("Product").
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *Repository) GetCartItem(userID, id string) (*store.CartItem, error) {
+	var item store.CartItem
+	err := r.db.First(&item, "id = ? AND user_id = ?", id, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindCartItem returns the user's cart line for the product and variant, so
+// adding the same item again raises its quantity instead of adding a line.
+func (r *Repository) FindCartItem(userID, productID string, variantID *string) (*store.CartItem, error) {
+	var item store.CartItem
+	query := r.db.Where("user_id = ? AND product_id = ?", userID, productID)
+	if variantID != nil {
+		query = query.Where("variant_id = ?", *variantID)
+	} else {
+		query = query.Where("variant_id IS NULL")
+	}
+	err := query.First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *Repository) SaveCartItem(item *store.CartItem) error {
+	return r.db.Omit("Product").Save(item).Error
+}
+
+func (r *Repository) DeleteCartItem(userID, id string) (bool, error) {
+	res := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&store.CartItem{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *Repository) ClearCart(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&store.CartItem{}).Error
+}
+
+// DeleteCartItemsTx removes the checked-out lines and reports how many were
+// still there; a concurrent checkout of the same cart finds fewer.
+func (r *Repository) DeleteCartItemsTx(tx *gorm.DB, userID string, ids []string) (int64, error) {
+	res := tx.Where("user_id = ? AND id IN ?", userID, ids).Delete(&store.CartItem{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *Repository) GetRedemptionOrder(userID, id string) (*store.RedemptionOrder, error) {
+	var order store.RedemptionOrder
+	err := r.db.Preload("Redemptions.Product").
+		First(&order, "id = ? AND user_id = ?", id, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *Repository) ListRedemptionOrders(userID string, page, limit int) ([]store.RedemptionOrder, int64, error) {
+	var orders []store.RedemptionOrder
+	var total int64
+
+	query := r.db.Model(&store.RedemptionOrder{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Redemptions.Product").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&orders).Error
+	return orders, total, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) FindProductsByExternalSKUs(skus []string) ([]store.Product, error) {
+	var products []store.Product
+	if len(skus) == 0 {
+		return products, nil
+	}
+	err := r.db.Where("external_sku IN ?", skus).Find(&products).Error
+	return products, err
+}
+
+// SaveCatalogProducts never overwrites stored stock; imported quantities are
+// applied as stock changes so they appear in the inventory log.
+func (r *Repository) SaveCatalogProducts(created, updated []*store.Product, stockChanges []*store.InventoryMovement) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if len(created) > 0 {
+			// Select every column so is_active=false is written instead of
+			// falling back to the column default.
+			if err := tx.Select("*").Omit("Category", "Variants").CreateInBatches(created, 200).Error; err != nil {
+				return err
+			}
+		}
+		for _, p := range updated {
+			if err := tx.Omit("Category", "Variants", "stock_quantity", "rating_average", "rating_count", "offer_redeemed_quantity").Save(p).Error; err != nil {
+				return err
+			}
+		}
+		for _, entry := range stockChanges {
+			if err := r.applyOptionalStockChangeTx(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveCatalogCategories saves categories in the given order so parents are
+// written before the children that reference them.
+func (r *Repository) SaveCatalogCategories(categories []*store.Category) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		for _, c := range categories {
+			if err := tx.Omit("Children").Save(c).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Repository) ProductsInBatches(batchSize int, fn func([]store.Product) error) error {
+	var batch []store.Product
+	return r.db.Order("id").FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
@@ -1,6 +1,16 @@
 package repository
 
-import "Start/internal/store"
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryCycle    = errors.New("category cycle")
+	ErrCategoryNotEmpty = errors.New("category is not empty")
+)
 
 func (r *Repository) GetAllCategories(parentID *string) ([]store.Category, error) {
 	var categories []store.Category
@@ -12,14 +22,25 @@ func (r *Repository) GetAllCategories(parentID *string) ([]store.Category, error
 	return categories, err
 }
 
-func (r *Repository) GetProductsByCategoryID(categoryID string, page, limit int) ([]*store.Product, int, error) {
+func (r *Repository) GetActiveCategories(parentID *string) ([]store.Category, error) {
+	var categories []store.Category
+	query := r.db.Model(&store.Category{}).Where("is_active = ?", true)
+	if parentID != nil {
+		query = query.Where("parent_category_id = ?", *parentID)
+	}
+	err := query.Find(&categories).Error
+	return categories, err
+}
+
+func (r *Repository) GetProductsByCategoryIDs(categoryIDs []string, page, limit int) ([]*store.Product, int, error) {
 	var products []*store.Product
 	var total int64
 
-	r.db.Model(&store.Product{}).Where("category_id = ?", categoryID).Count(&total)
+	r.db.Model(&store.Product{}).Where("category_id IN ? AND is_active = ?", categoryIDs, true).Count(&total)
 
 	offset := (page - 1) * limit
-	err := r.db.Where("category_id = ?", categoryID).
+	err := r.db.Preload("Category").
+		Where("category_id IN ? AND is_active = ?", categoryIDs, true).
 		Limit(limit).
 		Offset(offset).
 		Order("created_at DESC").
@@ -32,16 +53,186 @@ func (r *Repository) GetProductsByCategoryID(categoryID string, page, limit int)
 	return products, int(total), nil
 }
 
+// categorySubtreeQuery selects a category and all of its descendants. UNION
+// stops the recursion if the stored hierarchy already has a cycle.
+const categorySubtreeQuery = `WITH RECURSIVE subtree AS (
+	SELECT id FROM category WHERE id = ?
+	UNION
+	SELECT c.id FROM category c JOIN subtree s ON c.parent_category_id = s.id
+) SELECT id FROM subtree`
+
+func (r *Repository) GetCategorySubtreeIDs(id string) ([]string, error) {
+	var ids []string
+	err := r.db.Raw(categorySubtreeQuery, id).Scan(&ids).Error
+	return ids, err
+}
+
+type CategoryTreeRow struct {
+	ID               string
+	ParentCategoryID *string
+	Name             string
+	Description      string
+	Translations     datatypes.JSON
+	Depth            int
+	DirectCount      int64
+	TotalCount       int64
+}
+
+// FetchCategoryTree walks the hierarchy from the root categories and counts
+// the active products of each category and of its whole subtree. Rows come
+// parents first.
+func (r *Repository) FetchCategoryTree() ([]CategoryTreeRow, error) {
+	var rows []CategoryTreeRow
+	err := r.db.Raw(`WITH RECURSIVE tree AS (
+		SELECT id, parent_category_id, name, description, translations, 0 AS depth, ARRAY[id] AS path
+		FROM category WHERE parent_category_id IS NULL AND is_active
+		UNION ALL
+		SELECT c.id, c.parent_category_id, c.name, c.description, c.translations, t.depth + 1, t.path || c.id
+		FROM category c JOIN tree t ON c.parent_category_id = t.id
+		WHERE c.is_active AND c.id <> ALL(t.path)
+	), direct AS (
+		SELECT category_id, count(*) AS n FROM product WHERE is_active GROUP BY category_id
+	)
+	SELECT t.id, t.parent_category_id, t.name, t.description, t.translations, t.depth,
+		COALESCE(d.n, 0) AS direct_count,
+		(SELECT COALESCE(SUM(dd.n), 0) FROM tree sub JOIN direct dd ON dd.category_id = sub.id
+			WHERE t.id = ANY(sub.path)) AS total_count
+	FROM tree t LEFT JOIN direct d ON d.category_id = t.id
+	ORDER BY t.depth, t.name`).Scan(&rows).Error
+	return rows, err
+}
+
 func (r *Repository) CreateCategory(c *store.Category) error {
 	return r.db.Create(c).Error
 }
 
-func (r *Repository) UpdateCategory(c *store.Category) error {
-	return r.db.Save(c).Error
+// UpdateCategory writes only the columns in updates. A parent_category_id
+// entry re-parents the category under the same lock and cycle check as
+// MoveCategory, in the same transaction as the other columns.
+func (r *Repository) UpdateCategory(id string, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	return r.WithTx(func(tx *gorm.DB) error {
+		if parent, ok := updates["parent_category_id"]; ok {
+			if err := lockCategoriesTx(tx); err != nil {
+				return err
+			}
+			if parentID, _ := parent.(*string); parentID != nil {
+				if inside, err := inSubtreeTx(tx, id, *parentID); err != nil {
+					return err
+				} else if inside {
+					return ErrCategoryCycle
+				}
+			}
+		}
+		return tx.Model(&store.Category{}).Where("id = ?", id).Updates(updates).Error
+	})
+}
+
+// DeleteEmptyCategory deletes the category unless it still has children or
+// products, archived ones included. The check and the delete run under the
+// category lock so nothing can be added in between.
+func (r *Repository) DeleteEmptyCategory(id string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := lockCategoriesTx(tx); err != nil {
+			return err
+		}
+		var children, products int64
+		if err := tx.Model(&store.Category{}).Where("parent_category_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&store.Product{}).Where("category_id = ?", id).Count(&products).Error; err != nil {
+			return err
+		}
+		if children > 0 || products > 0 {
+			return ErrCategoryNotEmpty
+		}
+		return tx.Delete(&store.Category{}, "id = ?", id).Error
+	})
+}
+
+// ReassignAndDeleteCategory moves the category's products and children to the
+// target category and deletes it. The target must not be inside the deleted
+// category's subtree.
+func (r *Repository) ReassignAndDeleteCategory(id, targetID string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := lockCategoriesTx(tx); err != nil {
+			return err
+		}
+		if inside, err := inSubtreeTx(tx, id, targetID); err != nil {
+			return err
+		} else if inside {
+			return ErrCategoryCycle
+		}
+		if err := tx.Model(&store.Product{}).Where("category_id = ?", id).
+			Update("category_id", targetID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&store.Category{}).Where("parent_category_id = ?", id).
+			Update("parent_category_id", targetID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&store.Category{}, "id = ?", id).Error
+	})
+}
+
+// ArchiveCategorySubtree deactivates the category, its descendants and all of
+// their products.
+func (r *Repository) ArchiveCategorySubtree(id string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := lockCategoriesTx(tx); err != nil {
+			return err
+		}
+		var subtree []string
+		if err := tx.Raw(categorySubtreeQuery, id).Scan(&subtree).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&store.Product{}).Where("category_id IN ?", subtree).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&store.Category{}).Where("id IN ?", subtree).
+			Update("is_active", false).Error
+	})
+}
+
+// MoveCategory re-parents the category, and with it its whole subtree. A nil
+// parent makes it a root category.
+func (r *Repository) MoveCategory(id string, parentID *string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := lockCategoriesTx(tx); err != nil {
+			return err
+		}
+		if parentID != nil {
+			if inside, err := inSubtreeTx(tx, id, *parentID); err != nil {
+				return err
+			} else if inside {
+				return ErrCategoryCycle
+			}
+		}
+		return tx.Model(&store.Category{}).Where("id = ?", id).
+			Update("parent_category_id", parentID).Error
+	})
 }
 
-func (r *Repository) DeleteCategory(id string) error {
-	return r.db.Delete(&store.Category{}, "id = ?", id).Error
+// lockCategoriesTx serialises hierarchy changes so two concurrent moves cannot
+// pass their cycle checks and together form a cycle.
+func lockCategoriesTx(tx *gorm.DB) error {
+	return tx.Exec("LOCK TABLE category IN SHARE ROW EXCLUSIVE MODE").Error
+}
+
+func inSubtreeTx(tx *gorm.DB, rootID, id string) (bool, error) {
+	var ids []string
+	if err := tx.Raw(categorySubtreeQuery, rootID).Scan(&ids).Error; err != nil {
+		return false, err
+	}
+	for _, sub := range ids {
+		if sub == id {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (r *Repository) GetCategoryByID(id string) (*store.Category, error) {
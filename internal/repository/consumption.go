@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// CreateBillableAction writes every column so an action created inactive is
+// not turned active by the is_active default.
+func (r *Repository) CreateBillableAction(action *store.BillableAction) error {
+	return r.db.Select("*").Create(action).Error
+}
+
+func (r *Repository) UpdateBillableAction(action *store.BillableAction) error {
+	return r.db.Save(action).Error
+}
+
+func (r *Repository) GetBillableActionByID(id string) (*store.BillableAction, error) {
+	var action store.BillableAction
+	err := r.db.First(&action, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+func (r *Repository) GetBillableActionByKey(key string) (*store.BillableAction, error) {
+	var action store.BillableAction
+	err := r.db.First(&action, "key = ?", key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+func (r *Repository) ListBillableActions(activeOnly bool) ([]store.BillableAction, error) {
+	var actions []store.BillableAction
+	query := r.db.Model(&store.BillableAction{})
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("key ASC").Find(&actions).Error
+	return actions, err
+}
+
+// ConsumptionReferenceUsed reports whether the API key already billed or held
+// credits under the reference.
+func (r *Repository) ConsumptionReferenceUsed(apiKeyID, reference string) (bool, error) {
+	var usages, holds int64
+	if err := r.db.Model(&store.CreditUsage{}).
+		Where("api_key_id = ? AND reference = ? AND hold_id IS NULL", apiKeyID, reference).
+		Count(&usages).Error; err != nil {
+		return false, err
+	}
+	if err := r.db.Model(&store.CreditHold{}).
+		Where("api_key_id = ? AND reference = ?", apiKeyID, reference).
+		Count(&holds).Error; err != nil {
+		return false, err
+	}
+	return usages+holds > 0, nil
+}
+
+func (r *Repository) GetCreditHoldByID(id string) (*store.CreditHold, error) {
+	var hold store.CreditHold
+	err := r.db.First(&hold, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// CreateCreditHoldTx saves a new hold, failing with ErrDuplicateReference
+// when the API key already placed one under the same reference.
+func (r *Repository) CreateCreditHoldTx(tx *gorm.DB, hold *store.CreditHold) error {
+	if err := tx.Create(hold).Error; err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicateReference
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateCreditUsageTx saves a usage, failing with ErrDuplicateReference when
+// the API key already billed a direct usage under the same reference.
+func (r *Repository) CreateCreditUsageTx(tx *gorm.DB, usage *store.CreditUsage) error {
+	if err := tx.Create(usage).Error; err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicateReference
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) LockCreditHoldTx(tx *gorm.DB, id string) (*store.CreditHold, error) {
+	var hold store.CreditHold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&hold, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// ClaimExpiredHoldTx locks one open hold that has passed its expiry, skipping
+// rows locked by another scheduler instance. It returns nil when none is left.
+func (r *Repository) ClaimExpiredHoldTx(tx *gorm.DB, now time.Time) (*store.CreditHold, error) {
+	var hold store.CreditHold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND expires_at <= ?", "held", now).
+		Order("expires_at ASC").
+		First(&hold).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+type UsageReportRow struct {
+	ActionKey  string
+	Name       string
+	Events     int64
+	Quantity   int64
+	Credits    int64
+	LastUsedAt time.Time
+}
+
+// UsageReport totals the user's billed usage per action, optionally limited to
+// a date range.
+func (r *Repository) UsageReport(userID, dateFrom, dateTo string) ([]UsageReportRow, error) {
+	query := r.db.Table("credit_usage AS u").
+		Select(`u.action_key, COALESCE(MAX(a.name), u.action_key) AS name,
+			COUNT(*) AS events, SUM(u.quantity) AS quantity, SUM(u.credits) AS credits,
+			MAX(u.created_at) AS last_used_at`).
+		Joins("LEFT JOIN billable_action a ON a.key = u.action_key").
+		Where("u.user_id = ?", userID)
+	if dateFrom != "" {
+		query = query.Where("u.created_at >= ?", dateFrom)
+	}
+	if dateTo != "" {
+		query = query.Where("u.created_at <= ?", dateTo)
+	}
+
+	var rows []UsageReportRow
+	err := query.Group("u.action_key").Order("credits DESC").Scan(&rows).Error
+	return rows, err
+}
@@ -2,6 +2,11 @@ package repository
 
 import (
 	"Start/internal/store"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 func (r *Repository) GetPaginatedPackages(page, limit int, activeFilter *bool) ([]store.CreditPackage, int64, error) {
@@ -27,14 +32,189 @@ func (r *Repository) GetCreditPackageByID(id string) (*store.CreditPackage, erro
 	return &pkg, nil
 }
 
-func (r *Repository) CreateCreditPackage(pkg *store.CreditPackage) error {
-	return r.db.Create(pkg).Error
+func (r *Repository) CreateCreditPackage(pkg *store.CreditPackage, actorID *string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Create(pkg).Error; err != nil {
+			return err
+		}
+		return recordPackagePriceTx(tx, pkg, pkg.CreatedAt, actorID, nil)
+	})
 }
 
-func (r *Repository) UpdateCreditPackage(pkg *store.CreditPackage) error {
-	return r.db.Save(pkg).Error
+// UpdateCreditPackage saves the package and, when its price, credits or
+// points changed, closes the current price period and opens a new one.
+func (r *Repository) UpdateCreditPackage(pkg *store.CreditPackage, actorID *string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&store.CreditPackage{}, "id = ?", pkg.ID).Error; err != nil {
+			return err
+		}
+		current, err := currentPackagePriceTx(tx, pkg.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.PriceEGP != pkg.PriceEGP || current.Credits != pkg.Credits || current.RewardPoints != pkg.RewardPoints {
+			if err := recordPackagePriceTx(tx, pkg, time.Now(), actorID, nil); err != nil {
+				return err
+			}
+		}
+		return tx.Save(pkg).Error
+	})
 }
 
 func (r *Repository) DeleteCreditPackage(id string) error {
 	return r.db.Delete(&store.CreditPackage{}, "id = ?", id).Error
 }
+
+// GetCreditPackageWithPrice reads the package and its current price period
+// together. The share lock waits out a concurrent price update, so the two
+// always describe the same pricing.
+func (r *Repository) GetCreditPackageWithPrice(id string) (*store.CreditPackage, *store.CreditPackagePrice, error) {
+	var pkg store.CreditPackage
+	var price *store.CreditPackagePrice
+	err := r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&pkg, "id = ?", id).Error; err != nil {
+			return err
+		}
+		var err error
+		price, err = currentPackagePriceTx(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &pkg, price, nil
+}
+
+func (r *Repository) ListPackagePriceHistory(packageID string) ([]store.CreditPackagePrice, error) {
+	var history []store.CreditPackagePrice
+	err := r.db.Where("credit_package_id = ?", packageID).
+		Order("effective_from DESC").
+		Find(&history).Error
+	return history, err
+}
+
+func (r *Repository) CreatePriceChange(change *store.CreditPackagePriceChange) error {
+	return r.db.Create(change).Error
+}
+
+func (r *Repository) ListPriceChanges(packageID, status string) ([]store.CreditPackagePriceChange, error) {
+	var changes []store.CreditPackagePriceChange
+	query := r.db.Where("credit_package_id = ?", packageID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("effective_at ASC").Find(&changes).Error
+	return changes, err
+}
+
+// CancelPriceChange cancels a change that has not been applied yet. It
+// reports false when there is no such pending change.
+func (r *Repository) CancelPriceChange(packageID, id string) (bool, error) {
+	res := r.db.Model(&store.CreditPackagePriceChange{}).
+		Where("id = ? AND credit_package_id = ? AND status = ?", id, packageID, "pending").
+		Update("status", "cancelled")
+	return res.RowsAffected > 0, res.Error
+}
+
+// ApplyDuePriceChanges applies every pending price change whose time has
+// come, oldest first. A change older than the package's current price period
+// was overtaken by a manual update and is marked superseded instead. Rows are
+// locked with SKIP LOCKED so concurrent callers never apply a change twice.
+func (r *Repository) ApplyDuePriceChanges() error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		var due []store.CreditPackagePriceChange
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND effective_at <= ?", "pending", time.Now()).
+			Order("effective_at ASC").
+			Find(&due).Error; err != nil {
+			return err
+		}
+
+		for i := range due {
+			change := &due[i]
+			var pkg store.CreditPackage
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&pkg, "id = ?", change.CreditPackageID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					if err := setPriceChangeStatusTx(tx, change.ID, "cancelled"); err != nil {
+						return err
+					}
+					continue
+				}
+				return err
+			}
+
+			current, err := currentPackagePriceTx(tx, pkg.ID)
+			if err != nil {
+				return err
+			}
+			if current != nil && current.EffectiveFrom.After(change.EffectiveAt) {
+				if err := setPriceChangeStatusTx(tx, change.ID, "superseded"); err != nil {
+					return err
+				}
+				continue
+			}
+
+			pkg.PriceEGP = change.PriceEGP
+			pkg.Credits = change.Credits
+			pkg.RewardPoints = change.RewardPoints
+			if err := tx.Model(&pkg).Updates(map[string]interface{}{
+				"price_egp":     pkg.PriceEGP,
+				"credits":       pkg.Credits,
+				"reward_points": pkg.RewardPoints,
+			}).Error; err != nil {
+				return err
+			}
+			if err := recordPackagePriceTx(tx, &pkg, change.EffectiveAt, change.CreatedBy, &change.ID); err != nil {
+				return err
+			}
+			if err := setPriceChangeStatusTx(tx, change.ID, "applied"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func setPriceChangeStatusTx(tx *gorm.DB, id, status string) error {
+	updates := map[string]interface{}{"status": status}
+	if status == "applied" {
+		updates["applied_at"] = time.Now()
+	}
+	return tx.Model(&store.CreditPackagePriceChange{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func currentPackagePriceTx(tx *gorm.DB, packageID string) (*store.CreditPackagePrice, error) {
+	var price store.CreditPackagePrice
+	err := tx.Where("credit_package_id = ? AND effective_to IS NULL", packageID).
+		Order("effective_from DESC").
+		First(&price).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// recordPackagePriceTx closes the package's open price period at from and
+// opens a new one with the package's current pricing.
+func recordPackagePriceTx(tx *gorm.DB, pkg *store.CreditPackage, from time.Time, changedBy, changeID *string) error {
+	if err := tx.Model(&store.CreditPackagePrice{}).
+		Where("credit_package_id = ? AND effective_to IS NULL", pkg.ID).
+		Update("effective_to", from).Error; err != nil {
+		return err
+	}
+	return tx.Create(&store.CreditPackagePrice{
+		ID:              uuid.NewString(),
+		CreditPackageID: pkg.ID,
+		PriceEGP:        pkg.PriceEGP,
+		Credits:         pkg.Credits,
+		RewardPoints:    pkg.RewardPoints,
+		EffectiveFrom:   from,
+		ChangedBy:       changedBy,
+		PriceChangeID:   changeID,
+	}).Error
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
 
 type Repository struct {
 	db *gorm.DB
@@ -9,3 +13,12 @@ type Repository struct {
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
+
+// ErrDuplicateReference is returned when a write collides with a unique
+// reference index, meaning a concurrent call with the same reference won.
+var ErrDuplicateReference = errors.New("duplicate reference")
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
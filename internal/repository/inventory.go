@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+func (r *Repository) ApplyStockChange(entry *store.InventoryMovement) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		return r.ApplyStockChangeTx(tx, entry)
+	})
+}
+
+// ApplyStockChangeTx adjusts the stock of the entry's product, or of its
+// variant when one is set, and records the entry in the inventory log. A
+// change that would take stock below zero fails with ErrInsufficientStock.
+// When the product's stock drops to its low-stock threshold an admin
+// notification is raised, and when it comes back from zero the users who
+// wishlisted it are notified.
+func (r *Repository) ApplyStockChangeTx(tx *gorm.DB, entry *store.InventoryMovement) error {
+	var product store.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "name", "stock_quantity", "low_stock_threshold", "is_active").
+		First(&product, "id = ?", entry.ProductID).Error; err != nil {
+		return err
+	}
+
+	var stockAfter []int
+	if entry.VariantID != nil {
+		if err := tx.Raw(`UPDATE product_variant SET stock_quantity = stock_quantity + ?
+			WHERE id = ? AND product_id = ? AND stock_quantity + ? >= 0
+			RETURNING stock_quantity`,
+			entry.Delta, *entry.VariantID, entry.ProductID, entry.Delta).Scan(&stockAfter).Error; err != nil {
+			return err
+		}
+	} else {
+		if err := tx.Raw(`UPDATE product SET stock_quantity = stock_quantity + ?
+			WHERE id = ? AND stock_quantity + ? >= 0
+			RETURNING stock_quantity`,
+			entry.Delta, entry.ProductID, entry.Delta).Scan(&stockAfter).Error; err != nil {
+			return err
+		}
+	}
+	if len(stockAfter) == 0 {
+		return ErrInsufficientStock
+	}
+	entry.StockAfter = stockAfter[0]
+
+	productStock := entry.StockAfter
+	if entry.VariantID != nil {
+		if err := syncProductStockTx(tx, entry.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Model(&store.Product{}).Select("stock_quantity").
+			Where("id = ?", entry.ProductID).Row().Scan(&productStock); err != nil {
+			return err
+		}
+	}
+
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	entry.CreatedAt = time.Now()
+	if err := tx.Create(entry).Error; err != nil {
+		return err
+	}
+
+	if product.IsActive && product.StockQuantity <= 0 && productStock > 0 {
+		if err := notifyBackInStockTx(tx, &product); err != nil {
+			return err
+		}
+	}
+
+	threshold := product.LowStockThreshold
+	if threshold == nil || product.StockQuantity <= *threshold || productStock > *threshold {
+		return nil
+	}
+	return tx.Create(&store.AdminNotification{
+		ID:        uuid.NewString(),
+		Kind:      "low_stock",
+		ProductID: &product.ID,
+		Message:   fmt.Sprintf("%s is low on stock: %d left (threshold %d)", product.Name, productStock, *threshold),
+		CreatedAt: entry.CreatedAt,
+	}).Error
+}
+
+func (r *Repository) applyOptionalStockChangeTx(tx *gorm.DB, entry *store.InventoryMovement) error {
+	if entry == nil || entry.Delta == 0 {
+		return nil
+	}
+	return r.ApplyStockChangeTx(tx, entry)
+}
+
+func (r *Repository) ListInventoryMovements(productID string, page, limit int) ([]store.InventoryMovement, int64, error) {
+	var movements []store.InventoryMovement
+	var total int64
+
+	query := r.db.Model(&store.InventoryMovement{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&movements).Error
+	return movements, total, err
+}
+
+type StockDemandRow struct {
+	ProductID         string
+	Name              string
+	StockQuantity     int
+	LowStockThreshold *int
+	Redeemed          int
+}
+
+// FetchStockDemand returns the units redeemed since the given time for every
+// active product that had any redemptions in that period. Cancelled and
+// rejected redemptions returned their units and do not count.
+func (r *Repository) FetchStockDemand(since time.Time) ([]StockDemandRow, error) {
+	var rows []StockDemandRow
+	err := r.db.Table("product").
+		Select(`product.id AS product_id, product.name, product.stock_quantity,
+			product.low_stock_threshold, SUM(redemption.quantity) AS redeemed`).
+		Joins(`JOIN redemption ON redemption.product_id = product.id
+			AND redemption.created_at >= ? AND COALESCE(redemption.status, '') NOT IN ('cancelled', 'rejected')`, since).
+		Where("product.is_active").
+		Group("product.id").
+		Find(&rows).Error
+	return rows, err
+}
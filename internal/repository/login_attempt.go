@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+func (r *Repository) GetLoginAttempt(key string) (*store.LoginAttempt, error) {
+	var attempt store.LoginAttempt
+	err := r.db.First(&attempt, "key = ?", key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &attempt, err
+}
+
+// RecordLoginAttempt increments the counter for key and keeps the time of the
+// attempt before this one, starting over when that attempt is older than
+// staleBefore. The upsert serializes concurrent attempts on the same key.
+func (r *Repository) RecordLoginAttempt(key string, now, staleBefore time.Time) (*store.LoginAttempt, error) {
+	var attempt store.LoginAttempt
+	err := r.db.Raw(`
+		INSERT INTO login_attempt (key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempt.last_failure_at < ? THEN 1 ELSE login_attempt.failures + 1 END,
+			previous_failure_at = login_attempt.last_failure_at,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING key, failures, last_failure_at, previous_failure_at`,
+		key, now, staleBefore,
+	).Scan(&attempt).Error
+	return &attempt, err
+}
+
+// RecordLoginAttemptInWindow counts an attempt in a fixed window that opens
+// with the first attempt after windowStartedBefore; last_failure_at holds the
+// window start.
+func (r *Repository) RecordLoginAttemptInWindow(key string, now, windowStartedBefore time.Time) (*store.LoginAttempt, error) {
+	var attempt store.LoginAttempt
+	err := r.db.Raw(`
+		INSERT INTO login_attempt (key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempt.last_failure_at < ? THEN 1 ELSE login_attempt.failures + 1 END,
+			last_failure_at = CASE WHEN login_attempt.last_failure_at < ? THEN EXCLUDED.last_failure_at ELSE login_attempt.last_failure_at END
+		RETURNING key, failures, last_failure_at, previous_failure_at`,
+		key, now, windowStartedBefore, windowStartedBefore,
+	).Scan(&attempt).Error
+	return &attempt, err
+}
+
+func (r *Repository) ForgiveLoginAttempt(key string) error {
+	return r.db.Model(&store.LoginAttempt{}).Where("key = ?", key).
+		Update("failures", gorm.Expr("GREATEST(failures - 1, 0)")).Error
+}
+
+func (r *Repository) DeleteLoginAttempt(key string) error {
+	return r.db.Delete(&store.LoginAttempt{}, "key = ?", key).Error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"gorm.io/gorm"
+	"time"
+)
+
+func (r *Repository) ListNotifications(userID string, unreadOnly bool, page, limit int) ([]store.Notification, int64, error) {
+	var notifications []store.Notification
+	var total int64
+
+	query := r.db.Model(&store.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *Repository) MarkNotificationRead(userID, id string) (bool, error) {
+	res := r.db.Model(&store.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	return res.RowsAffected > 0, res.Error
+}
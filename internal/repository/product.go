@@ -12,20 +12,7 @@ func (r *Repository) GetAllProducts(filters types.ProductFilters, page, limit in
 	var products []store.Product
 	var count int64
 
-	query := r.db.Model(&store.Product{})
-
-	if filters.CategoryID != "" {
-		query = query.Where("category_id = ?", filters.CategoryID)
-	}
-	if filters.IsOffer != nil {
-		query = query.Where("is_offer = ?", *filters.IsOffer)
-	}
-	if filters.MinPoints > 0 {
-		query = query.Where("redemption_points >= ?", filters.MinPoints)
-	}
-	if filters.MaxPoints > 0 {
-		query = query.Where("redemption_points <= ?", filters.MaxPoints)
-	}
+	query := applyProductFilters(r.db.Model(&store.Product{}), filters)
 
 	query.Count(&count)
 
@@ -38,41 +25,133 @@ func (r *Repository) GetAllProducts(filters types.ProductFilters, page, limit in
 	return products, count, err
 }
 
-func (r *Repository) SearchProducts(queryStr string, filters types.ProductFilters, page, limit int) ([]store.Product, int64, error) {
-	var products []store.Product
+type ProductSearchRow struct {
+	store.Product
+	CategoryName       string
+	Rank               float64
+	NameHighlight      string
+	DescriptionSnippet string
+}
+
+// SearchProducts matches the query against the product search document using
+// the given text search configuration, falling back to trigram similarity on
+// the name so misspelled queries still find something. The category name,
+// highlight and snippet use the locale's translation where there is one.
+func (r *Repository) SearchProducts(queryStr, config, locale string, filters types.ProductFilters, page, limit int) ([]ProductSearchRow, int64, error) {
+	var rows []ProductSearchRow
 	var count int64
 
-	query := r.db.Model(&store.Product{}).
-		Where("name ILIKE ? OR description ILIKE ?", "%"+queryStr+"%", "%"+queryStr+"%")
+	query := applyProductFilters(r.productSearchQuery(queryStr, config), filters)
 
-	if filters.CategoryID != "" {
-		query = query.Where("category_id = ?", filters.CategoryID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Select(`product.*, COALESCE(NULLIF(category.translations->?->>'name', ''), category.name) AS category_name,
+			ts_rank_cd(product.search_vector, q) + word_similarity(?, `+productNamesExpr+`) AS rank,
+			ts_headline(?::regconfig, COALESCE(NULLIF(product.translations->?->>'name', ''), product.name), q,
+				'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+			ts_headline(?::regconfig, COALESCE(NULLIF(product.translations->?->>'description', ''), product.description), q,
+				'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS description_snippet`,
+			locale, queryStr, config, locale, config, locale).
+		Joins("LEFT JOIN category ON category.id = product.category_id").
+		Order("rank DESC, product.created_at DESC").
+		Limit(limit).Offset((page - 1) * limit).
+		Find(&rows).Error
+	return rows, count, err
+}
+
+// productNamesExpr matches the trigram index over the product name and its
+// translations.
+const productNamesExpr = `translated_text(product.name, product.translations::jsonb, 'name')`
+
+func (r *Repository) productSearchQuery(queryStr, config string) *gorm.DB {
+	return r.db.Table("product").
+		Joins("CROSS JOIN websearch_to_tsquery(?::regconfig, ?) AS q", config, queryStr).
+		Where("product.search_vector @@ q OR ? <% "+productNamesExpr, queryStr)
+}
+
+// activeOfferCondition matches products whose offer is live right now: flagged
+// as an offer, inside its window and with units left under the offer cap.
+const activeOfferCondition = `(product.is_offer
+	AND (product.offer_starts_at IS NULL OR product.offer_starts_at <= now())
+	AND (product.offer_ends_at IS NULL OR product.offer_ends_at > now())
+	AND (product.offer_quantity_cap IS NULL OR product.offer_redeemed_quantity < product.offer_quantity_cap))`
+
+const effectivePointsExpr = `(CASE WHEN product.offer_points IS NOT NULL AND ` + activeOfferCondition + `
+	THEN product.offer_points ELSE product.redemption_points END)`
+
+func applyProductFilters(query *gorm.DB, filters types.ProductFilters) *gorm.DB {
+	query = query.Where("product.is_active = ?", !filters.Archived)
+
+	if len(filters.CategoryIDs) > 0 && !filters.IncludeDescendants {
+		query = query.Where("product.category_id IN ?", filters.CategoryIDs)
+	} else if len(filters.CategoryIDs) > 0 {
+		query = query.Where(`product.category_id IN (
+			WITH RECURSIVE selected AS (
+				SELECT id FROM category WHERE id IN ?
+				UNION
+				SELECT c.id FROM category c JOIN selected s ON c.parent_category_id = s.id
+			) SELECT id FROM selected)`, filters.CategoryIDs)
+	}
+	if len(filters.Tags) > 0 {
+		query = query.Where(`jsonb_typeof(product.tags) = 'array' AND EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(product.tags) AS tag WHERE tag IN ?)`, filters.Tags)
 	}
 	if filters.IsOffer != nil {
-		query = query.Where("is_offer = ?", *filters.IsOffer)
+		if *filters.IsOffer {
+			query = query.Where(activeOfferCondition)
+		} else {
+			query = query.Where("NOT " + activeOfferCondition)
+		}
+	}
+	if filters.InStock != nil {
+		if *filters.InStock {
+			query = query.Where("product.stock_quantity > 0")
+		} else {
+			query = query.Where("product.stock_quantity <= 0")
+		}
 	}
 	if filters.MinPoints > 0 {
-		query = query.Where("redemption_points >= ?", filters.MinPoints)
+		query = query.Where(effectivePointsExpr+" >= ?", filters.MinPoints)
 	}
 	if filters.MaxPoints > 0 {
-		query = query.Where("redemption_points <= ?", filters.MaxPoints)
+		query = query.Where(effectivePointsExpr+" <= ?", filters.MaxPoints)
 	}
-
-	query.Count(&count)
-	err := query.Limit(limit).Offset((page - 1) * limit).Find(&products).Error
-	return products, count, err
+	return query
 }
 
-func (r *Repository) CreateProduct(p *store.Product) error {
-	return r.db.Create(p).Error
+func (r *Repository) CreateProduct(p *store.Product, stockChange *store.InventoryMovement) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Create(p).Error; err != nil {
+			return err
+		}
+		return r.applyOptionalStockChangeTx(tx, stockChange)
+	})
 }
 
-func (r *Repository) UpdateProduct(p *store.Product) error {
-	return r.db.Save(p).Error
+// UpdateProduct leaves the stored stock alone; stock only moves through
+// stockChange so every change is recorded in the inventory log. The offer
+// redeemed count is likewise only written when resetOfferCount is set, so an
+// edit never writes back a count that redemptions have moved on from.
+func (r *Repository) UpdateProduct(p *store.Product, stockChange *store.InventoryMovement, resetOfferCount bool) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Omit("stock_quantity", "rating_average", "rating_count", "offer_redeemed_quantity").Save(p).Error; err != nil {
+			return err
+		}
+		if resetOfferCount {
+			if err := tx.Model(&store.Product{}).Where("id = ?", p.ID).
+				UpdateColumn("offer_redeemed_quantity", 0).Error; err != nil {
+				return err
+			}
+		}
+		return r.applyOptionalStockChangeTx(tx, stockChange)
+	})
 }
 
-func (r *Repository) DeleteProduct(id string) error {
-	return r.db.Delete(&store.Product{}, "id = ?", id).Error
+func (r *Repository) SetProductActive(id string, active bool) error {
+	return r.db.Model(&store.Product{}).Where("id = ?", id).Update("is_active", active).Error
 }
 
 func (r *Repository) GetProductByID(id string) (*store.Product, error) {
@@ -84,8 +163,22 @@ func (r *Repository) GetProductByID(id string) (*store.Product, error) {
 	return &p, err
 }
 
+func (r *Repository) GetActiveProductWithCategory(id string) (*store.Product, error) {
+	var p store.Product
+	err := r.db.Preload("Category").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("created_at")
+		}).
+		First(&p, "id = ? AND is_active = ?", id, true).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &p, err
+}
+
 func (r *Repository) FetchProductsByPreferences(includeCats, excludeCats []string, minPoints, maxPoints, limit int) ([]store.Product, error) {
 	query := r.db.Preload("Category").
+		Where("is_active = ?", true).
 		Where("redemption_points >= ? AND redemption_points <= ?", minPoints, maxPoints).
 		Limit(limit)
 
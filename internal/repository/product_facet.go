@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"Start/internal/types"
+	"fmt"
+	"strings"
+)
+
+func (r *Repository) CountSearchProductsByCategory(queryStr, config string, filters types.ProductFilters) (map[string]int64, error) {
+	var rows []struct {
+		CategoryID string
+		Count      int64
+	}
+	err := applyProductFilters(r.productSearchQuery(queryStr, config), filters).
+		Select("product.category_id, count(*) AS count").
+		Group("product.category_id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *Repository) CountSearchProductsByTag(queryStr, config string, filters types.ProductFilters, limit int) ([]types.FacetCount, error) {
+	var facets []types.FacetCount
+	err := applyProductFilters(r.productSearchQuery(queryStr, config), filters).
+		Joins(`CROSS JOIN LATERAL jsonb_array_elements_text(
+			CASE WHEN jsonb_typeof(product.tags) = 'array' THEN product.tags ELSE '[]'::jsonb END) AS tag`).
+		Select("tag AS value, count(*) AS count").
+		Group("tag").
+		Order("count DESC, value").
+		Limit(limit).
+		Find(&facets).Error
+	return facets, err
+}
+
+func (r *Repository) CountSearchProductsByPoints(queryStr, config string, filters types.ProductFilters, ranges []types.PointsRange) ([]int64, error) {
+	columns := make([]string, len(ranges))
+	args := make([]interface{}, 0, len(ranges)*2)
+	for i, pr := range ranges {
+		if pr.Max > 0 {
+			columns[i] = fmt.Sprintf("count(*) FILTER (WHERE %s BETWEEN ? AND ?) AS bucket_%d", effectivePointsExpr, i)
+			args = append(args, pr.Min, pr.Max)
+		} else {
+			columns[i] = fmt.Sprintf("count(*) FILTER (WHERE %s >= ?) AS bucket_%d", effectivePointsExpr, i)
+			args = append(args, pr.Min)
+		}
+	}
+
+	counts := make([]int64, len(ranges))
+	dest := make([]interface{}, len(ranges))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+
+	err := applyProductFilters(r.productSearchQuery(queryStr, config), filters).
+		Select(strings.Join(columns, ", "), args...).
+		Row().Scan(dest...)
+	return counts, err
+}
+
+func (r *Repository) CountSearchProductsByOffer(queryStr, config string, filters types.ProductFilters) (offer, regular int64, err error) {
+	err = applyProductFilters(r.productSearchQuery(queryStr, config), filters).
+		Select("count(*) FILTER (WHERE "+activeOfferCondition+"), count(*) FILTER (WHERE NOT "+activeOfferCondition+")").
+		Row().Scan(&offer, &regular)
+	return offer, regular, err
+}
+
+func (r *Repository) CountSearchProductsByStock(queryStr, config string, filters types.ProductFilters) (inStock, outOfStock int64, err error) {
+	err = applyProductFilters(r.productSearchQuery(queryStr, config), filters).
+		Select("count(*) FILTER (WHERE product.stock_quantity > 0), count(*) FILTER (WHERE product.stock_quantity <= 0)").
+		Row().Scan(&inStock, &outOfStock)
+	return inStock, outOfStock, err
+}
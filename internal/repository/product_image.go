@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) CreateProductImage(img *store.ProductImage) error {
+	return r.db.Create(img).Error
+}
+
+func (r *Repository) ListProductImages(productID string) ([]store.ProductImage, error) {
+	var images []store.ProductImage
+	err := r.db.Where("product_id = ?", productID).Order("position, created_at").Find(&images).Error
+	return images, err
+}
+
+func (r *Repository) GetProductImageByID(id string) (*store.ProductImage, error) {
+	var img store.ProductImage
+	err := r.db.First(&img, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &img, err
+}
+
+func (r *Repository) DeleteProductImage(id string) error {
+	return r.db.Delete(&store.ProductImage{}, "id = ?", id).Error
+}
+
+func (r *Repository) ReorderProductImages(productID string, orderedIDs []string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		for i, id := range orderedIDs {
+			if err := tx.Model(&store.ProductImage{}).
+				Where("id = ? AND product_id = ?", id, productID).
+				Update("position", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetPrimaryProductImage marks one image as primary and mirrors its URL into
+// product.image_url, which listings and recommendations still read. An empty
+// imageID clears the primary image.
+func (r *Repository) SetPrimaryProductImage(productID, imageID, imageURL string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Model(&store.ProductImage{}).
+			Where("product_id = ? AND is_primary", productID).
+			Update("is_primary", false).Error; err != nil {
+			return err
+		}
+		if imageID != "" {
+			if err := tx.Model(&store.ProductImage{}).
+				Where("id = ? AND product_id = ?", imageID, productID).
+				Update("is_primary", true).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&store.Product{}).Where("id = ?", productID).Update("image_url", imageURL).Error
+	})
+}
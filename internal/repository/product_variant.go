@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) CreateProductVariant(v *store.ProductVariant, stockChange *store.InventoryMovement) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Create(v).Error; err != nil {
+			return err
+		}
+		if err := syncProductStockTx(tx, v.ProductID); err != nil {
+			return err
+		}
+		return r.applyOptionalStockChangeTx(tx, stockChange)
+	})
+}
+
+func (r *Repository) UpdateProductVariant(v *store.ProductVariant, stockChange *store.InventoryMovement) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Omit("stock_quantity").Save(v).Error; err != nil {
+			return err
+		}
+		if err := syncProductStockTx(tx, v.ProductID); err != nil {
+			return err
+		}
+		return r.applyOptionalStockChangeTx(tx, stockChange)
+	})
+}
+
+func (r *Repository) GetProductVariantByID(id string) (*store.ProductVariant, error) {
+	var v store.ProductVariant
+	err := r.db.First(&v, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &v, err
+}
+
+func (r *Repository) FindProductVariantBySKU(sku string) (*store.ProductVariant, error) {
+	var v store.ProductVariant
+	err := r.db.First(&v, "sku = ?", sku).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &v, err
+}
+
+func (r *Repository) ListProductVariants(productID string) ([]store.ProductVariant, error) {
+	var variants []store.ProductVariant
+	err := r.db.Where("product_id = ? AND is_active = ?", productID, true).
+		Order("created_at").Find(&variants).Error
+	return variants, err
+}
+
+func (r *Repository) CountProductVariants(productID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&store.ProductVariant{}).
+		Where("product_id = ? AND is_active = ?", productID, true).Count(&count).Error
+	return count, err
+}
+
+// ProductIDsWithVariants returns which of the given products have active
+// variants, and so get their stock from them.
+func (r *Repository) ProductIDsWithVariants(productIDs []string) (map[string]bool, error) {
+	var ids []string
+	if len(productIDs) > 0 {
+		if err := r.db.Model(&store.ProductVariant{}).Distinct("product_id").
+			Where("product_id IN ? AND is_active = ?", productIDs, true).
+			Pluck("product_id", &ids).Error; err != nil {
+			return nil, err
+		}
+	}
+	result := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// syncProductStockTx keeps the product's stock equal to the sum of its active
+// variants so listings and stock filters stay correct for variant products.
+func syncProductStockTx(tx *gorm.DB, productID string) error {
+	return tx.Exec(`UPDATE product SET stock_quantity = (
+		SELECT COALESCE(SUM(stock_quantity), 0) FROM product_variant
+		WHERE product_id = ? AND is_active
+	) WHERE id = ?`, productID, productID).Error
+}
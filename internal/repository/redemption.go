@@ -4,6 +4,7 @@ import (
 	"Start/internal/store"
 	"errors"
 	"gorm.io/gorm"
+	"time"
 )
 
 func (r *Repository) WithTx(fn func(tx *gorm.DB) error) error {
@@ -15,9 +16,39 @@ func (r *Repository) WithTx(fn func(tx *gorm.DB) error) error {
 	return tx.Commit().Error
 }
 
-func (r *Repository) DecrementStockTx(tx *gorm.DB, productID string, quantity int) error {
-	return tx.Model(&store.Product{}).Where("id = ? AND stock_quantity >= ?", productID, quantity).
-		UpdateColumn("stock_quantity", gorm.Expr("stock_quantity - ?", quantity)).Error
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrOfferUnavailable  = errors.New("offer unavailable")
+)
+
+// ReserveOfferQuantityTx counts quantity against the product's offer cap,
+// failing if the offer has ended or the cap would be exceeded.
+func (r *Repository) ReserveOfferQuantityTx(tx *gorm.DB, productID string, quantity int) error {
+	res := tx.Model(&store.Product{}).
+		Where("id = ?", productID).
+		Where(`is_offer
+			AND (offer_starts_at IS NULL OR offer_starts_at <= now())
+			AND (offer_ends_at IS NULL OR offer_ends_at > now())
+			AND (offer_quantity_cap IS NULL OR offer_redeemed_quantity + ? <= offer_quantity_cap)`, quantity).
+		UpdateColumn("offer_redeemed_quantity", gorm.Expr("offer_redeemed_quantity + ?", quantity))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrOfferUnavailable
+	}
+	return nil
+}
+
+// ReleaseOfferQuantityTx gives quantity back to the product's offer cap when a
+// redemption made at redeemedAt is refunded. Redemptions from before the
+// current offer window were counted against an earlier run that has since
+// been reset, so they release nothing.
+func (r *Repository) ReleaseOfferQuantityTx(tx *gorm.DB, productID string, quantity int, redeemedAt time.Time) error {
+	return tx.Model(&store.Product{}).
+		Where("id = ?", productID).
+		Where("offer_starts_at IS NULL OR offer_starts_at <= ?", redeemedAt).
+		UpdateColumn("offer_redeemed_quantity", gorm.Expr("GREATEST(offer_redeemed_quantity - ?, 0)", quantity)).Error
 }
 
 func (r *Repository) ListRedemptionsByUser(userID string, page, limit int) ([]*store.Redemption, int64, error) {
@@ -69,11 +100,6 @@ func (r *Repository) FetchAllRedemptions(page, limit int, status, dateFrom, date
 	return redemptions, int(count), err
 }
 
-func (r *Repository) UpdateRedemptionStatus(id, status string) error {
-	return r.db.Model(&store.Redemption{}).Where("id = ?", id).
-		Updates(map[string]interface{}{"status": status}).Error
-}
-
 func (r *Repository) FindRedemptionByID(id string) (*store.Redemption, error) {
 	var redemption store.Redemption
 	err := r.db.First(&redemption, "id = ?", id).Error
@@ -82,3 +108,42 @@ func (r *Repository) FindRedemptionByID(id string) (*store.Redemption, error) {
 	}
 	return &redemption, err
 }
+
+// LockRedemptionLimitTx serialises redemptions of a product by one user until
+// the transaction ends, so limit checks and the insert cannot interleave.
+func (r *Repository) LockRedemptionLimitTx(tx *gorm.DB, userID, productID string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "redemption:"+userID+":"+productID).Error
+}
+
+type RedemptionUsage struct {
+	LastDay        int
+	LastWeek       int
+	Lifetime       int
+	LastRedeemedAt *time.Time
+}
+
+// RedemptionUsageTx counts the units of the product the user redeemed in the
+// rolling day and week before now and ever, ignoring cancelled and rejected
+// redemptions.
+// LastRedeemedAt leaves out the redemptions of orderID, so the lines of one
+// checkout do not hold each other back, while every other redemption counts
+// however close it is to now.
+func (r *Repository) RedemptionUsageTx(tx *gorm.DB, userID, productID string, now time.Time, orderID *string) (*RedemptionUsage, error) {
+	var usage RedemptionUsage
+	err := tx.Model(&store.Redemption{}).
+		Select(`COALESCE(SUM(quantity) FILTER (WHERE created_at > ?), 0) AS last_day,
+			COALESCE(SUM(quantity) FILTER (WHERE created_at > ?), 0) AS last_week,
+			COALESCE(SUM(quantity), 0) AS lifetime,
+			MAX(created_at) FILTER (WHERE order_id IS NULL OR order_id IS DISTINCT FROM ?) AS last_redeemed_at`,
+			now.Add(-24*time.Hour), now.Add(-7*24*time.Hour), orderID).
+		Where("user_id = ? AND product_id = ? AND COALESCE(status, '') NOT IN ?", userID, productID, []string{"cancelled", "rejected"}).
+		Scan(&usage).Error
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (r *Repository) RedemptionUsage(userID, productID string, now time.Time) (*RedemptionUsage, error) {
+	return r.RedemptionUsageTx(r.db, userID, productID, now, nil)
+}
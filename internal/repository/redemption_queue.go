@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// redemptionStatusExpr treats redemptions stored without a status as pending.
+const redemptionStatusExpr = "COALESCE(NULLIF(redemption.status, ''), 'pending')"
+
+type RedemptionQueueQuery struct {
+	Statuses      []string
+	ProductID     string
+	CategoryID    string
+	AssignedTo    string
+	Unassigned    bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// FetchRedemptionQueue lists redemptions oldest first, so the ones closest to
+// breaching the SLA come up top.
+func (r *Repository) FetchRedemptionQueue(q RedemptionQueueQuery, page, limit int) ([]store.Redemption, int64, error) {
+	query := r.db.Model(&store.Redemption{}).Where(redemptionStatusExpr+" IN ?", q.Statuses)
+	if q.ProductID != "" {
+		query = query.Where("redemption.product_id = ?", q.ProductID)
+	}
+	if q.CategoryID != "" {
+		query = query.Where(`redemption.product_id IN (
+			SELECT id FROM product WHERE category_id IN (`+categorySubtreeQuery+`))`, q.CategoryID)
+	}
+	if q.Unassigned {
+		query = query.Where("redemption.assigned_to IS NULL")
+	} else if q.AssignedTo != "" {
+		query = query.Where("redemption.assigned_to = ?", q.AssignedTo)
+	}
+	if q.CreatedAfter != nil {
+		query = query.Where("redemption.created_at >= ?", *q.CreatedAfter)
+	}
+	if q.CreatedBefore != nil {
+		query = query.Where("redemption.created_at <= ?", *q.CreatedBefore)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var redemptions []store.Redemption
+	err := query.Preload("Product.Category").
+		Order("redemption.created_at ASC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&redemptions).Error
+	return redemptions, total, err
+}
+
+func (r *Repository) LockRedemptionTx(tx *gorm.DB, id string) (*store.Redemption, error) {
+	var redemption store.Redemption
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&redemption, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+func (r *Repository) SaveRedemptionTx(tx *gorm.DB, redemption *store.Redemption) error {
+	return tx.Omit("Product").Save(redemption).Error
+}
+
+func (r *Repository) GetRedemptionWithCategory(id string) (*store.Redemption, error) {
+	var redemption store.Redemption
+	err := r.db.Preload("Product.Category").First(&redemption, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
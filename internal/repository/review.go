@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) HasDeliveredRedemption(userID, productID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&store.Redemption{}).
+		Where("user_id = ? AND product_id = ? AND status = ?", userID, productID, "delivered").
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *Repository) CreateReview(rv *store.ProductReview) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Omit("User").Create(rv).Error; err != nil {
+			return err
+		}
+		return refreshProductRatingTx(tx, rv.ProductID)
+	})
+}
+
+func (r *Repository) UpdateReview(rv *store.ProductReview) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Omit("User").Save(rv).Error; err != nil {
+			return err
+		}
+		return refreshProductRatingTx(tx, rv.ProductID)
+	})
+}
+
+func (r *Repository) GetReviewByID(id string) (*store.ProductReview, error) {
+	var rv store.ProductReview
+	err := r.db.Preload("User").First(&rv, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &rv, err
+}
+
+func (r *Repository) FindReviewByAuthor(productID, userID string) (*store.ProductReview, error) {
+	var rv store.ProductReview
+	err := r.db.First(&rv, "product_id = ? AND user_id = ?", productID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &rv, err
+}
+
+// ListReviews returns reviews newest first. Empty status or productID match
+// every review.
+func (r *Repository) ListReviews(productID, status string, page, limit int) ([]store.ProductReview, int64, error) {
+	var reviews []store.ProductReview
+	var total int64
+
+	query := r.db.Model(&store.ProductReview{})
+	if productID != "" {
+		query = query.Where("product_id = ?", productID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&reviews).Error
+	return reviews, total, err
+}
+
+// refreshProductRatingTx recomputes the product's cached rating from its
+// published reviews.
+func refreshProductRatingTx(tx *gorm.DB, productID string) error {
+	return tx.Exec(`UPDATE product SET
+		rating_average = COALESCE((
+			SELECT ROUND(AVG(rating)::numeric, 2) FROM product_review
+			WHERE product_id = ? AND status = 'published'
+		), 0),
+		rating_count = (
+			SELECT count(*) FROM product_review
+			WHERE product_id = ? AND status = 'published'
+		)
+	WHERE id = ?`, productID, productID, productID).Error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) GetSetting(key string) (*store.Setting, error) {
+	var setting store.Setting
+	err := r.db.First(&setting, "key = ?", key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func (r *Repository) SaveSetting(setting *store.Setting) error {
+	return r.db.Save(setting).Error
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// CreateSubscriptionPlan writes every column so an inactive plan is not
+// turned active by the is_active default.
+func (r *Repository) CreateSubscriptionPlan(plan *store.SubscriptionPlan) error {
+	return r.db.Select("*").Create(plan).Error
+}
+
+func (r *Repository) UpdateSubscriptionPlan(plan *store.SubscriptionPlan) error {
+	return r.db.Save(plan).Error
+}
+
+func (r *Repository) GetSubscriptionPlanByID(id string) (*store.SubscriptionPlan, error) {
+	var plan store.SubscriptionPlan
+	err := r.db.First(&plan, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *Repository) ListSubscriptionPlans(activeOnly bool) ([]store.SubscriptionPlan, error) {
+	var plans []store.SubscriptionPlan
+	query := r.db.Model(&store.SubscriptionPlan{})
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("price_egp ASC").Find(&plans).Error
+	return plans, err
+}
+
+func (r *Repository) GetSubscriptionByID(id string) (*store.Subscription, error) {
+	var sub store.Subscription
+	err := r.db.Preload("Plan").First(&sub, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *Repository) GetOpenSubscription(userID string) (*store.Subscription, error) {
+	var sub store.Subscription
+	err := r.db.Preload("Plan").
+		Where("user_id = ? AND status <> ?", userID, "cancelled").
+		First(&sub).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *Repository) ListUserSubscriptions(userID string) ([]store.Subscription, error) {
+	var subs []store.Subscription
+	err := r.db.Preload("Plan").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *Repository) LockSubscriptionTx(tx *gorm.DB, id string) (*store.Subscription, error) {
+	var sub store.Subscription
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&sub, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// ClaimDueSubscriptionTx locks one subscription whose renewal is due. Rows
+// already locked by another scheduler instance, subscriptions with a charge
+// still being settled and the IDs in skip are passed over, and nil is
+// returned when nothing is left to renew.
+func (r *Repository) ClaimDueSubscriptionTx(tx *gorm.DB, now time.Time, skip []string) (*store.Subscription, error) {
+	var sub store.Subscription
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status IN ? AND renews_at <= ?", []string{"trialing", "active", "past_due"}, now).
+		Where("NOT EXISTS (SELECT 1 FROM purchase WHERE purchase.subscription_id = subscription.id AND purchase.status = ?)", "pending")
+	if len(skip) > 0 {
+		query = query.Where("id NOT IN ?", skip)
+	}
+	err := query.Order("renews_at ASC").First(&sub).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *Repository) SaveSubscriptionTx(tx *gorm.DB, sub *store.Subscription) error {
+	return tx.Omit("Plan").Save(sub).Error
+}
+
+func (r *Repository) HasPendingSubscriptionPurchaseTx(tx *gorm.DB, subscriptionID string) (bool, error) {
+	var count int64
+	err := tx.Model(&store.Purchase{}).
+		Where("subscription_id = ? AND status = ?", subscriptionID, "pending").
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListStalePendingPurchases returns subscription charges created before
+// before that were never settled, e.g. because the process stopped between
+// charging and settling.
+func (r *Repository) ListStalePendingPurchases(before time.Time) ([]store.Purchase, error) {
+	var purchases []store.Purchase
+	err := r.db.Where("status = ? AND subscription_id IS NOT NULL AND created_at < ?", "pending", before).
+		Order("created_at ASC").
+		Find(&purchases).Error
+	return purchases, err
+}
+
+// SettlePurchaseTx moves a pending purchase to status. It reports false when
+// the purchase was already settled, e.g. by a concurrent retry.
+func (r *Repository) SettlePurchaseTx(tx *gorm.DB, id, status, transactionID string) (bool, error) {
+	res := tx.Model(&store.Purchase{}).
+		Where("id = ? AND status = ?", id, "pending").
+		Updates(map[string]interface{}{"status": status, "payment_transaction_id": transactionID})
+	return res.RowsAffected == 1, res.Error
+}
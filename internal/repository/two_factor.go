@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+func (r *Repository) SetPendingTOTPSecret(userID, secret string) error {
+	return r.db.Model(&store.User{}).Where("id = ?", userID).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_enabled": false}).Error
+}
+
+func (r *Repository) EnableTOTP(userID string, step int64, codeHashes []string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Model(&store.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"totp_enabled": true, "totp_last_used_step": step}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodesTx(tx, userID, codeHashes)
+	})
+}
+
+func (r *Repository) DisableTOTP(userID string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Model(&store.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"totp_secret": "", "totp_enabled": false, "totp_last_used_step": 0}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&store.RecoveryCode{}).Error
+	})
+}
+
+// ConsumeTOTPStep records step as used, failing when the same or a later
+// step was already accepted so a code cannot be replayed.
+func (r *Repository) ConsumeTOTPStep(userID string, step int64) (bool, error) {
+	res := r.db.Model(&store.User{}).
+		Where("id = ? AND totp_last_used_step < ?", userID, step).
+		Update("totp_last_used_step", step)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *Repository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		return replaceRecoveryCodesTx(tx, userID, codeHashes)
+	})
+}
+
+func (r *Repository) ListUnusedRecoveryCodes(userID string) ([]store.RecoveryCode, error) {
+	var codes []store.RecoveryCode
+	err := r.db.Where("user_id = ? AND used_at IS NULL", userID).Find(&codes).Error
+	return codes, err
+}
+
+func (r *Repository) MarkRecoveryCodeUsed(id string) (bool, error) {
+	res := r.db.Model(&store.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *Repository) SetMFAChallenge(userID, challengeID string) error {
+	return r.db.Model(&store.User{}).Where("id = ?", userID).Update("mfa_challenge_id", challengeID).Error
+}
+
+// ConsumeMFAChallenge clears the user's outstanding challenge if it is still
+// challengeID, so a challenge token completes at most one login.
+func (r *Repository) ConsumeMFAChallenge(userID, challengeID string) (bool, error) {
+	res := r.db.Model(&store.User{}).
+		Where("id = ? AND mfa_challenge_id = ?", userID, challengeID).
+		Update("mfa_challenge_id", "")
+	return res.RowsAffected == 1, res.Error
+}
+
+func replaceRecoveryCodesTx(tx *gorm.DB, userID string, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&store.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	codes := make([]store.RecoveryCode, 0, len(codeHashes))
+	for _, h := range codeHashes {
+		codes = append(codes, store.RecoveryCode{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			CodeHash:  h,
+			CreatedAt: now,
+		})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
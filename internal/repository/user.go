@@ -20,7 +20,7 @@ func (r *Repository) IsEmailOrUsernameTaken(email, username string) (bool, error
 
 func (r *Repository) FindByEmail(email string) (*store.User, error) {
 	var user store.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.db.Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+func (r *Repository) FindUserIdentity(provider, subject string) (*store.UserIdentity, error) {
+	var identity store.UserIdentity
+	err := r.db.First(&identity, "provider = ? AND subject = ?", provider, subject).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &identity, err
+}
+
+func (r *Repository) CreateUserIdentity(identity *store.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+func (r *Repository) CreateUserWithIdentity(user *store.User, identity *store.UserIdentity) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(identity).Error
+	})
+}
+
+func (r *Repository) CreateOIDCLoginState(state *store.OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// TakeOIDCLoginState deletes and returns the state so it can only be used once.
+func (r *Repository) TakeOIDCLoginState(state string) (*store.OIDCLoginState, error) {
+	var s store.OIDCLoginState
+	res := r.db.Raw("DELETE FROM oidc_login_state WHERE state = ? RETURNING *", state).Scan(&s)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (r *Repository) DeleteOIDCLoginStatesBefore(t time.Time) error {
+	return r.db.Where("created_at < ?", t).Delete(&store.OIDCLoginState{}).Error
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"Start/internal/store"
+	"errors"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -24,31 +27,79 @@ func (r *Repository) GetWalletByUserID(userID string) (*store.Wallet, error) {
 	return &wallet, nil
 }
 
-func (r *Repository) AddToWallet(userID string, credits int, points int) error {
-	return r.db.Model(&store.Wallet{}).
-		Where("user_id = ?", userID).
-		Updates(map[string]interface{}{
-			"credits_balance": gorm.Expr("credits_balance + ?", credits),
-			"points_balance":  gorm.Expr("points_balance + ?", points),
-			"updated_at":      time.Now(),
-		}).Error
-}
-
 func (r *Repository) UpdateWallet(wallet *store.Wallet) error {
 	return r.db.Save(wallet).Error
 }
 
-func (r *Repository) UpdateWalletCredits(userID string, amount int) error {
-	return r.db.Model(&store.Wallet{}).Where("user_id = ?", userID).
-		UpdateColumn("credits_balance", gorm.Expr("credits_balance + ?", amount)).Error
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+func (r *Repository) ApplyWalletChange(entry *store.WalletTransaction) error {
+	return r.WithTx(func(tx *gorm.DB) error {
+		return r.ApplyWalletChangeTx(tx, entry)
+	})
 }
 
-func (r *Repository) UpdateWalletPoints(userID string, amount int) error {
-	return r.db.Model(&store.Wallet{}).Where("user_id = ?", userID).
-		Update("points_balance", gorm.Expr("points_balance + ?", amount)).Error
+// ApplyWalletChangeTx adjusts the user's balances by the entry's deltas and
+// records the entry in the wallet ledger. A change that would take either
+// balance below zero fails with ErrInsufficientBalance. Points credited here
+// can make wishlisted products affordable, which notifies the user.
+func (r *Repository) ApplyWalletChangeTx(tx *gorm.DB, entry *store.WalletTransaction) error {
+	now := time.Now()
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&store.Wallet{
+		ID:        uuid.NewString(),
+		UserID:    entry.UserID,
+		UpdatedAt: now,
+	}).Error; err != nil {
+		return err
+	}
+
+	res := tx.Model(&store.Wallet{}).
+		Where("user_id = ? AND points_balance + ? >= 0 AND credits_balance + ? >= 0",
+			entry.UserID, entry.PointsDelta, entry.CreditsDelta).
+		Updates(map[string]interface{}{
+			"points_balance":  gorm.Expr("points_balance + ?", entry.PointsDelta),
+			"credits_balance": gorm.Expr("credits_balance + ?", entry.CreditsDelta),
+			"updated_at":      now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInsufficientBalance
+	}
+
+	if entry.PointsDelta > 0 {
+		if err := notifyAffordableTx(tx, entry.UserID, entry.PointsDelta); err != nil {
+			return err
+		}
+	}
+
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	entry.CreatedAt = now
+	if err := tx.Create(entry).Error; err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicateReference
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *Repository) DeductPointsTx(tx *gorm.DB, userID string, points int) error {
-	return tx.Model(&store.Wallet{}).Where("user_id = ? AND points_balance >= ?", userID, points).
-		UpdateColumn("points_balance", gorm.Expr("points_balance - ?", points)).Error
+	return r.ApplyWalletChangeTx(tx, &store.WalletTransaction{
+		UserID:      userID,
+		PointsDelta: -points,
+		Reason:      "redemption",
+	})
+}
+
+func (r *Repository) FindWalletTransactionByReference(apiKeyID, referenceID string) (*store.WalletTransaction, error) {
+	var entry store.WalletTransaction
+	err := r.db.First(&entry, "api_key_id = ? AND reference_id = ?", apiKeyID, referenceID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &entry, err
 }
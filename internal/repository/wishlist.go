@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"Start/internal/store"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+func (r *Repository) AddWishlistItem(userID, productID string) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&store.WishlistItem{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		ProductID: productID,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+func (r *Repository) RemoveWishlistItem(userID, productID string) (bool, error) {
+	res := r.db.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&store.WishlistItem{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *Repository) ListWishlistItems(userID string, page, limit int) ([]store.WishlistItem, int64, error) {
+	var items []store.WishlistItem
+	var total int64
+
+	query := r.db.Model(&store.WishlistItem{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Product.Category").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&items).Error
+	return items, total, err
+}
+
+type WishlistDemandRow struct {
+	ProductID     string
+	Name          string
+	StockQuantity int
+	Wishlisted    int64
+}
+
+func (r *Repository) FetchMostWishlistedOutOfStock(limit int) ([]WishlistDemandRow, error) {
+	var rows []WishlistDemandRow
+	err := r.db.Table("product").
+		Select("product.id AS product_id, product.name, product.stock_quantity, count(*) AS wishlisted").
+		Joins("JOIN wishlist_item ON wishlist_item.product_id = product.id").
+		Where("product.is_active AND product.stock_quantity <= 0").
+		Group("product.id").
+		Order("wishlisted DESC, product.name").
+		Limit(limit).
+		Find(&rows).Error
+	return rows, err
+}
+
+// notifyBackInStockTx tells everyone who wishlisted the product that it can be
+// redeemed again.
+func notifyBackInStockTx(tx *gorm.DB, product *store.Product) error {
+	return tx.Exec(`INSERT INTO notification (id, user_id, kind, product_id, message, created_at)
+		SELECT gen_random_uuid()::text, user_id, 'back_in_stock', product_id, ?, now()
+		FROM wishlist_item WHERE product_id = ?`,
+		product.Name+" is back in stock", product.ID).Error
+}
+
+// notifyAffordableTx runs after the user's points balance grew by delta and
+// notifies them about every wishlisted product whose price the new balance has
+// just reached, using the offer price while an offer is live. Each wishlist
+// item is only notified once.
+func notifyAffordableTx(tx *gorm.DB, userID string, delta int) error {
+	return tx.Exec(`WITH due AS (
+		UPDATE wishlist_item w SET affordable_notified_at = now()
+		FROM product, wallet b
+		WHERE w.user_id = ? AND b.user_id = w.user_id AND product.id = w.product_id
+			AND product.is_active AND w.affordable_notified_at IS NULL
+			AND `+effectivePointsExpr+` <= b.points_balance
+			AND `+effectivePointsExpr+` > b.points_balance - ?
+		RETURNING w.user_id, product.id AS product_id, product.name
+	)
+	INSERT INTO notification (id, user_id, kind, product_id, message, created_at)
+	SELECT gen_random_uuid()::text, user_id, 'affordable', product_id,
+		'You now have enough points for ' || name, now()
+	FROM due`, userID, delta).Error
+}
@@ -2,16 +2,18 @@ package service
 
 import (
 	"Start/internal/repository"
+	"Start/internal/store"
 	"Start/internal/types"
 	"errors"
 )
 
 type adminService struct {
-	repo *repository.Repository
+	repo     *repository.Repository
+	throttle *LoginThrottle
 }
 
-func NewAdminService(repo *repository.Repository) AdminService {
-	return &adminService{repo: repo}
+func NewAdminService(repo *repository.Repository, throttle *LoginThrottle) AdminService {
+	return &adminService{repo: repo, throttle: throttle}
 }
 
 func (s *adminService) GetAdminDashboardStats() (*types.DashboardStatsResponse, error) {
@@ -85,23 +87,7 @@ func (s *adminService) GetAllRedemptions(page, limit int, status, dateFrom, date
 	return result, total, nil
 }
 
-func (s *adminService) UpdateRedemptionStatus(id string, status string) error {
-	if status != "pending" && status != "delivered" && status != "cancelled" {
-		return errors.New("invalid status")
-	}
-
-	r, err := s.repo.FindRedemptionByID(id)
-	if err != nil {
-		return err
-	}
-	if r == nil {
-		return errors.New("not found")
-	}
-
-	return s.repo.UpdateRedemptionStatus(id, status)
-}
-
-func (s *adminService) ManageUserCredits(userID, action string, amount int) error {
+func (s *adminService) ManageUserCredits(adminID, userID, action string, amount int) error {
 	if action != "add" && action != "subtract" {
 		return errors.New("invalid action")
 	}
@@ -114,13 +100,18 @@ func (s *adminService) ManageUserCredits(userID, action string, amount int) erro
 		return errors.New("user not found")
 	}
 
-	if action == "add" {
-		return s.repo.UpdateWalletCredits(userID, amount)
+	if action == "subtract" {
+		amount = -amount
 	}
-	return s.repo.UpdateWalletCredits(userID, -amount)
+	return s.repo.ApplyWalletChange(&store.WalletTransaction{
+		UserID:       userID,
+		CreditsDelta: amount,
+		Reason:       "admin_adjustment",
+		ActorUserID:  &adminID,
+	})
 }
 
-func (s *adminService) ManageUserPoints(userID, action string, amount int) error {
+func (s *adminService) ManageUserPoints(adminID, userID, action string, amount int) error {
 	if action != "add" && action != "subtract" {
 		return errors.New("invalid action")
 	}
@@ -133,10 +124,15 @@ func (s *adminService) ManageUserPoints(userID, action string, amount int) error
 		return errors.New("user not found")
 	}
 
-	if action == "add" {
-		return s.repo.UpdateWalletPoints(userID, amount)
+	if action == "subtract" {
+		amount = -amount
 	}
-	return s.repo.UpdateWalletPoints(userID, -amount)
+	return s.repo.ApplyWalletChange(&store.WalletTransaction{
+		UserID:      userID,
+		PointsDelta: amount,
+		Reason:      "admin_adjustment",
+		ActorUserID: &adminID,
+	})
 }
 
 func (s *adminService) UpdateUserStatus(userID, status string) error {
@@ -154,3 +150,15 @@ func (s *adminService) UpdateUserStatus(userID, status string) error {
 
 	return s.repo.UpdateUserStatus(userID, status)
 }
+
+func (s *adminService) UnlockUser(userID string) error {
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	return s.throttle.Unlock(user.Email)
+}
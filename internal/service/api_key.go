@@ -0,0 +1,173 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"net"
+	"strings"
+	"time"
+)
+
+var apiKeyScopes = map[string]bool{
+	"points:award":    true,
+	"wallets:read":    true,
+	"credits:consume": true,
+}
+
+type apiKeyService struct {
+	repo *repository.Repository
+}
+
+func NewAPIKeyService(repo *repository.Repository) APIKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+func (s *apiKeyService) CreateAPIKey(adminID string, input types.CreateAPIKeyRequest) (*types.CreatedAPIKeyResponse, error) {
+	for _, scope := range input.Scopes {
+		if !apiKeyScopes[scope] {
+			return nil, errors.New("invalid scope")
+		}
+	}
+	for _, entry := range input.AllowedIPs {
+		if _, _, err := net.ParseCIDR(entry); err != nil && net.ParseIP(entry) == nil {
+			return nil, errors.New("invalid ip allowlist")
+		}
+	}
+
+	prefix, secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, err
+	}
+	rawKey := "rk_" + prefix + "_" + secret
+
+	scopes, _ := json.Marshal(input.Scopes)
+	if input.AllowedIPs == nil {
+		input.AllowedIPs = []string{}
+	}
+	allowed, _ := json.Marshal(input.AllowedIPs)
+
+	k := &store.APIKey{
+		ID:         uuid.NewString(),
+		Name:       input.Name,
+		Prefix:     prefix,
+		KeyHash:    hashAPIKey(rawKey),
+		Scopes:     scopes,
+		AllowedIPs: allowed,
+		CreatedBy:  adminID,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.CreateAPIKey(k); err != nil {
+		return nil, err
+	}
+
+	return &types.CreatedAPIKeyResponse{APIKeyResponse: *ToAPIKeyResponse(k), Key: rawKey}, nil
+}
+
+func (s *apiKeyService) ListAPIKeys() ([]*types.APIKeyResponse, error) {
+	keys, err := s.repo.ListAPIKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*types.APIKeyResponse{}
+	for i := range keys {
+		res = append(res, ToAPIKeyResponse(&keys[i]))
+	}
+	return res, nil
+}
+
+func (s *apiKeyService) RevokeAPIKey(id string) error {
+	k, err := s.repo.FindAPIKeyByID(id)
+	if err != nil {
+		return err
+	}
+	if k == nil {
+		return errors.New("api key not found")
+	}
+	return s.repo.RevokeAPIKey(id)
+}
+
+func (s *apiKeyService) AuthenticateAPIKey(rawKey, clientIP, scope string) (string, error) {
+	// The secret is base64url and may itself contain "_", so only the first
+	// two separators split the key.
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != "rk" {
+		return "", errors.New("invalid api key")
+	}
+
+	k, err := s.repo.FindAPIKeyByPrefix(parts[1])
+	if err != nil {
+		return "", err
+	}
+	if k == nil || k.RevokedAt != nil ||
+		subtle.ConstantTimeCompare([]byte(k.KeyHash), []byte(hashAPIKey(rawKey))) != 1 {
+		return "", errors.New("invalid api key")
+	}
+
+	if !ipAllowed(k.AllowedIPs, clientIP) {
+		return "", errors.New("ip not allowed")
+	}
+
+	var scopes []string
+	_ = json.Unmarshal(k.Scopes, &scopes)
+	if !containsString(scopes, scope) {
+		return "", errors.New("insufficient scope")
+	}
+
+	_ = s.repo.TouchAPIKey(k.ID, clientIP)
+	return k.ID, nil
+}
+
+func newAPIKeySecret() (string, string, error) {
+	buf := make([]byte, 36)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(buf[:4]), base64.RawURLEncoding.EncodeToString(buf[4:]), nil
+}
+
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func ipAllowed(allowlist []byte, clientIP string) bool {
+	var entries []string
+	_ = json.Unmarshal(allowlist, &entries)
+	if len(entries) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, entry := range entries {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, val string) bool {
+	for _, item := range list {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
@@ -12,11 +12,12 @@ import (
 )
 
 type authService struct {
-	repo *repository.Repository
+	repo     *repository.Repository
+	throttle *LoginThrottle
 }
 
-func NewAuthService(repo *repository.Repository) AuthService {
-	return &authService{repo: repo}
+func NewAuthService(repo *repository.Repository, throttle *LoginThrottle) AuthService {
+	return &authService{repo: repo, throttle: throttle}
 }
 
 func (s *authService) SignUp(input types.SignUpInput) (*store.User, error) {
@@ -58,7 +59,12 @@ func (s *authService) SignUp(input types.SignUpInput) (*store.User, error) {
 	return user, nil
 }
 
-func (s *authService) Login(input types.LoginInput) (*types.LoginResponse, error) {
+func (s *authService) Login(input types.LoginInput, clientIP string) (*types.LoginResponse, error) {
+	now := time.Now()
+	if err := s.throttle.Attempt(input.Email, clientIP, now); err != nil {
+		return nil, err
+	}
+
 	user, err := s.repo.FindByEmail(input.Email)
 	if err != nil {
 		return nil, errors.New("invalid credentials")
@@ -69,7 +75,16 @@ func (s *authService) Login(input types.LoginInput) (*types.LoginResponse, error
 		return nil, errors.New("invalid credentials")
 	}
 
-	access, refresh, err := utils.GenerateTokens(user.ID, user.Email, user.Role)
+	if user.TOTPEnabled {
+		return issueMFAChallenge(s.repo, user.ID)
+	}
+
+	_ = s.throttle.Succeed(user.Email, clientIP)
+	return issueLoginResponse(user)
+}
+
+func issueLoginResponse(user *store.User) (*types.LoginResponse, error) {
+	access, refresh, err := utils.GenerateTokens(user.ID, user.Email, user.Role, user.TOTPEnabled)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +103,7 @@ func (s *authService) Login(input types.LoginInput) (*types.LoginResponse, error
 }
 
 func (s *authService) RefreshToken(refreshToken string) (*types.TokenPair, error) {
-	claims, err := utils.VerifyToken(refreshToken, utils.RefreshTokenSecret)
+	claims, err := utils.VerifyRefreshToken(refreshToken)
 	if err != nil {
 		return nil, err
 	}
@@ -96,8 +111,14 @@ func (s *authService) RefreshToken(refreshToken string) (*types.TokenPair, error
 	userID := claims["userId"].(string)
 	email := claims["email"].(string)
 	role := claims["role"].(string)
+	mfa, _ := claims["mfa"].(bool)
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
 
-	accessToken, refreshToken, err := utils.GenerateTokens(userID, email, role)
+	accessToken, refreshToken, err := utils.GenerateTokens(userID, email, role, mfa && user.TOTPEnabled)
 	if err != nil {
 		return nil, err
 	}
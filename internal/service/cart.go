@@ -0,0 +1,284 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type cartService struct {
+	repo        *repository.Repository
+	redemptions *redemptionService
+}
+
+func NewCartService(repo *repository.Repository) CartService {
+	return &cartService{repo: repo, redemptions: &redemptionService{repo: repo}}
+}
+
+// GetCart prices every line at the live points and stock. Lines that cannot
+// be redeemed as they stand are reported rather than dropped.
+func (s *cartService) GetCart(userID string) (*types.CartResponse, error) {
+	items, err := s.repo.ListCartItems(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cart := &types.CartResponse{Items: make([]types.CartItemResponse, 0, len(items))}
+	for i := range items {
+		item := &items[i]
+		line := types.CartItemResponse{
+			ID: item.ID,
+			Product: types.RedemptionProduct{
+				ID:           item.Product.ID,
+				Name:         item.Product.Name,
+				RewardPoints: item.Product.RedemptionPoints,
+			},
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		}
+
+		var variant *store.ProductVariant
+		if item.VariantID != nil {
+			if variant, err = s.repo.GetProductVariantByID(*item.VariantID); err != nil {
+				return nil, err
+			}
+		}
+		line.UnitPoints = unitPoints(&item.Product, variant, now)
+		line.LinePoints = line.UnitPoints * item.Quantity
+		line.AvailableStock = item.Product.StockQuantity
+		if variant != nil {
+			line.AvailableStock = variant.StockQuantity
+		}
+		if _, _, err := s.redemptions.resolveLine(userID, item.ProductID, derefString(item.VariantID), item.Quantity, now); err != nil {
+			line.Problem = err.Error()
+		}
+
+		cart.TotalPoints += line.LinePoints
+		cart.Items = append(cart.Items, line)
+	}
+
+	if wallet, err := s.repo.GetWalletByUserID(userID); err == nil {
+		cart.PointsBalance = wallet.PointsBalance
+	}
+	cart.CanCheckout = len(cart.Items) > 0 && cart.PointsBalance >= cart.TotalPoints
+	for _, line := range cart.Items {
+		if line.Problem != "" {
+			cart.CanCheckout = false
+		}
+	}
+	return cart, nil
+}
+
+func (s *cartService) AddItem(userID string, input types.AddCartItemRequest) (*types.CartResponse, error) {
+	var variantID *string
+	if input.VariantID != "" {
+		variantID = &input.VariantID
+	}
+	item, err := s.repo.FindCartItem(userID, input.ProductID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if item == nil {
+		item = &store.CartItem{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			ProductID: input.ProductID,
+			VariantID: variantID,
+			CreatedAt: now,
+		}
+	}
+	quantity := item.Quantity + input.Quantity
+	if _, _, err := s.redemptions.resolveLine(userID, input.ProductID, input.VariantID, quantity, now); err != nil {
+		return nil, err
+	}
+
+	item.Quantity = quantity
+	item.UpdatedAt = now
+	if err := s.repo.SaveCartItem(item); err != nil {
+		return nil, err
+	}
+	return s.GetCart(userID)
+}
+
+func (s *cartService) UpdateItem(userID, itemID string, quantity int) (*types.CartResponse, error) {
+	item, err := s.repo.GetCartItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, errors.New("cart item not found")
+	}
+
+	now := time.Now()
+	if _, _, err := s.redemptions.resolveLine(userID, item.ProductID, derefString(item.VariantID), quantity, now); err != nil {
+		return nil, err
+	}
+
+	item.Quantity = quantity
+	item.UpdatedAt = now
+	if err := s.repo.SaveCartItem(item); err != nil {
+		return nil, err
+	}
+	return s.GetCart(userID)
+}
+
+func (s *cartService) RemoveItem(userID, itemID string) (*types.CartResponse, error) {
+	deleted, err := s.repo.DeleteCartItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, errors.New("cart item not found")
+	}
+	return s.GetCart(userID)
+}
+
+func (s *cartService) ClearCart(userID string) error {
+	return s.repo.ClearCart(userID)
+}
+
+// Checkout redeems every line of the cart in one transaction: the points for
+// all lines are taken in a single wallet change and one short line fails the
+// whole order, leaving the cart as it was.
+func (s *cartService) Checkout(userID string) (*types.RedemptionOrderResponse, error) {
+	items, err := s.repo.ListCartItems(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+
+	now := time.Now()
+	order := &store.RedemptionOrder{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		ItemCount: len(items),
+		CreatedAt: now,
+	}
+	ids := make([]string, 0, len(items))
+	redemptions := make([]*store.Redemption, 0, len(items))
+	products := make(map[string]*store.Product, len(items))
+	for i := range items {
+		item := &items[i]
+		product, variant, err := s.redemptions.resolveLine(userID, item.ProductID, derefString(item.VariantID), item.Quantity, now)
+		if err != nil {
+			return nil, err
+		}
+		points := item.Quantity * unitPoints(product, variant, now)
+		order.PointsUsed += points
+		products[product.ID] = product
+		ids = append(ids, item.ID)
+		redemptions = append(redemptions, &store.Redemption{
+			ID:         uuid.NewString(),
+			UserID:     userID,
+			ProductID:  product.ID,
+			VariantID:  variantIDPtr(variant),
+			Status:     "pending",
+			Quantity:   item.Quantity,
+			PointsUsed: points,
+			CreatedAt:  now,
+			OrderID:    &order.ID,
+		})
+	}
+
+	wallet, err := s.repo.GetWalletByUserID(userID)
+	if err != nil || wallet == nil {
+		return nil, errors.New("user wallet not found")
+	}
+	if wallet.PointsBalance < order.PointsUsed {
+		return nil, errors.New("insufficient points")
+	}
+
+	if err := s.repo.WithTx(func(tx *gorm.DB) error {
+		deleted, err := s.repo.DeleteCartItemsTx(tx, userID, ids)
+		if err != nil {
+			return err
+		}
+		if deleted != int64(len(ids)) {
+			return errors.New("cart changed")
+		}
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if err := s.repo.ApplyWalletChangeTx(tx, &store.WalletTransaction{
+			UserID:      userID,
+			PointsDelta: -order.PointsUsed,
+			Reason:      "redemption",
+			ReferenceID: order.ID,
+		}); err != nil {
+			return err
+		}
+		for _, r := range redemptions {
+			if err := s.redemptions.redeemTx(tx, products[r.ProductID], r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, redemptionTxError(err)
+	}
+
+	for _, r := range redemptions {
+		r.Product = *products[r.ProductID]
+		order.Redemptions = append(order.Redemptions, *r)
+	}
+	return toRedemptionOrderResponse(order), nil
+}
+
+func (s *cartService) GetOrders(userID string, page, limit int) ([]types.RedemptionOrderResponse, types.PaginationMeta, error) {
+	orders, total, err := s.repo.ListRedemptionOrders(userID, page, limit)
+	if err != nil {
+		return nil, types.PaginationMeta{}, err
+	}
+	res := make([]types.RedemptionOrderResponse, 0, len(orders))
+	for i := range orders {
+		res = append(res, *toRedemptionOrderResponse(&orders[i]))
+	}
+	totalPages := (int(total) + limit - 1) / limit
+	return res, types.PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   int(total),
+		ItemsPerPage: limit,
+	}, nil
+}
+
+func (s *cartService) GetOrder(userID, id string) (*types.RedemptionOrderResponse, error) {
+	order, err := s.repo.GetRedemptionOrder(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errors.New("order not found")
+	}
+	return toRedemptionOrderResponse(order), nil
+}
+
+func toRedemptionOrderResponse(o *store.RedemptionOrder) *types.RedemptionOrderResponse {
+	res := &types.RedemptionOrderResponse{
+		ID:         o.ID,
+		PointsUsed: o.PointsUsed,
+		ItemCount:  o.ItemCount,
+		Items:      make([]*types.RedemptionResponse, 0, len(o.Redemptions)),
+		CreatedAt:  o.CreatedAt.Format(time.RFC3339),
+	}
+	for i := range o.Redemptions {
+		res.Items = append(res.Items, ToRedemptionResponse(&o.Redemptions[i]))
+	}
+	return res
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
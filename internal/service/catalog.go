@@ -0,0 +1,636 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	catalogMaxTags      = 20
+	catalogMaxTagLength = 50
+	catalogExportBatch  = 500
+)
+
+var (
+	catalogProductColumns  = []string{"external_sku", "name", "description", "category", "redemption_points", "stock_quantity", "is_offer", "is_active", "tags", "image_url"}
+	catalogCategoryColumns = []string{"name", "description", "parent"}
+)
+
+type catalogService struct {
+	repo *repository.Repository
+}
+
+func NewCatalogService(repo *repository.Repository) CatalogService {
+	return &catalogService{repo: repo}
+}
+
+type productImportRow struct {
+	line int
+	row  types.CatalogProductRow
+	errs []string
+}
+
+type categoryImportRow struct {
+	line int
+	row  types.CatalogCategoryRow
+}
+
+// ImportProducts validates every row and upserts products by external SKU.
+// Nothing is written unless all rows are valid, and a dry run only reports
+// what would happen.
+func (s *catalogService) ImportProducts(actorID, format string, body io.Reader, dryRun bool) (*types.ImportResult, error) {
+	rows, err := parseProductRows(format, body)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.GetAllCategories(nil)
+	if err != nil {
+		return nil, err
+	}
+	lookup := newCategoryLookup(categories)
+
+	skus := make([]string, 0, len(rows))
+	for _, r := range rows {
+		if sku := strings.TrimSpace(r.row.ExternalSKU); sku != "" {
+			skus = append(skus, sku)
+		}
+	}
+	existing, err := s.repo.FindProductsByExternalSKUs(skus)
+	if err != nil {
+		return nil, err
+	}
+	bySKU := make(map[string]*store.Product, len(existing))
+	existingIDs := make([]string, len(existing))
+	for i := range existing {
+		bySKU[*existing[i].ExternalSKU] = &existing[i]
+		existingIDs[i] = existing[i].ID
+	}
+	withVariants, err := s.repo.ProductIDsWithVariants(existingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.ImportResult{DryRun: dryRun, Total: len(rows), Errors: []types.ImportRowError{}}
+	var created, updated []*store.Product
+	var stockChanges []*store.InventoryMovement
+	seen := map[string]int{}
+
+	for _, r := range rows {
+		row := r.row
+		row.ExternalSKU = strings.TrimSpace(row.ExternalSKU)
+		row.Name = strings.TrimSpace(row.Name)
+		errs := append([]string{}, r.errs...)
+
+		if row.ExternalSKU == "" {
+			errs = append(errs, "external_sku is required")
+		} else if first, dup := seen[row.ExternalSKU]; dup {
+			errs = append(errs, fmt.Sprintf("external_sku duplicates row %d", first))
+		} else {
+			seen[row.ExternalSKU] = r.line
+		}
+		if row.Name == "" {
+			errs = append(errs, "name is required")
+		}
+		if row.RedemptionPoints <= 0 {
+			errs = append(errs, "redemption_points must be greater than 0")
+		}
+		if row.StockQuantity < 0 {
+			errs = append(errs, "stock_quantity must not be negative")
+		} else if p, ok := bySKU[row.ExternalSKU]; ok && withVariants[p.ID] && row.StockQuantity != p.StockQuantity {
+			errs = append(errs, "stock_quantity cannot change: stock is managed by variants")
+		}
+		category, catErr := lookup.resolve(row.Category)
+		if catErr != "" {
+			errs = append(errs, catErr)
+		}
+		tags, tagErrs := normalizeTags(row.Tags)
+		errs = append(errs, tagErrs...)
+
+		if len(errs) > 0 {
+			result.Errors = append(result.Errors, types.ImportRowError{Row: r.line, Key: row.ExternalSKU, Errors: errs})
+			continue
+		}
+
+		tagsJSON, err := json.Marshal(tags)
+		if err != nil {
+			return nil, err
+		}
+
+		p, ok := bySKU[row.ExternalSKU]
+		if !ok {
+			sku := row.ExternalSKU
+			p = &store.Product{ID: uuid.NewString(), ExternalSKU: &sku, IsActive: true, CreatedAt: time.Now()}
+			created = append(created, p)
+			if stock := stockChange(actorID, p.ID, nil, 0, row.StockQuantity, "restock", "catalog import"); stock != nil {
+				stockChanges = append(stockChanges, stock)
+			}
+		} else {
+			updated = append(updated, p)
+			if stock := stockChange(actorID, p.ID, nil, p.StockQuantity, row.StockQuantity, "correction", "catalog import"); stock != nil {
+				stockChanges = append(stockChanges, stock)
+			}
+		}
+		p.Name = row.Name
+		p.Description = row.Description
+		p.CategoryID = category.ID
+		p.RedemptionPoints = row.RedemptionPoints
+		p.IsOffer = row.IsOffer
+		p.Tags = tagsJSON
+		if row.IsActive != nil {
+			p.IsActive = *row.IsActive
+		}
+		if row.ImageURL != "" {
+			p.ImageURL = row.ImageURL
+		}
+	}
+
+	result.Created, result.Updated = len(created), len(updated)
+	if dryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+	if err := s.repo.SaveCatalogProducts(created, updated, stockChanges); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ImportCategories upserts categories by name. A parent may be an existing
+// category or one defined earlier in the same file.
+func (s *catalogService) ImportCategories(format string, body io.Reader, dryRun bool) (*types.ImportResult, error) {
+	rows, err := parseCategoryRows(format, body)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.GetAllCategories(nil)
+	if err != nil {
+		return nil, err
+	}
+	lookup := newCategoryLookup(categories)
+
+	result := &types.ImportResult{DryRun: dryRun, Total: len(rows), Errors: []types.ImportRowError{}}
+	var pending []*store.Category
+	createdIDs := map[string]bool{}
+	touched := map[string]bool{}
+
+	for _, r := range rows {
+		row := r.row
+		row.Name = strings.TrimSpace(row.Name)
+		var errs []string
+
+		if row.Name == "" {
+			errs = append(errs, "name is required")
+		}
+		c, lookupErr := lookup.byName(row.Name)
+		if lookupErr != "" {
+			errs = append(errs, lookupErr)
+		}
+		if c != nil && touched[c.ID] {
+			errs = append(errs, "category appears more than once")
+		}
+
+		var parentID *string
+		if strings.TrimSpace(row.Parent) != "" {
+			parent, parentErr := lookup.resolve(row.Parent)
+			switch {
+			case parentErr != "":
+				errs = append(errs, "parent: "+parentErr)
+			case c != nil && lookup.isSelfOrDescendant(parent.ID, c.ID):
+				errs = append(errs, "parent would create a cycle")
+			default:
+				parentID = &parent.ID
+			}
+		}
+
+		if len(errs) > 0 {
+			result.Errors = append(result.Errors, types.ImportRowError{Row: r.line, Key: row.Name, Errors: errs})
+			continue
+		}
+
+		if c == nil {
+			c = &store.Category{ID: uuid.NewString()}
+			createdIDs[c.ID] = true
+		}
+		c.Name = row.Name
+		c.Description = row.Description
+		c.ParentCategoryID = parentID
+		c.IsActive = true
+		lookup.add(c)
+		touched[c.ID] = true
+		pending = append(pending, c)
+	}
+
+	for _, c := range pending {
+		if createdIDs[c.ID] {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+	if dryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+	if err := s.repo.SaveCatalogCategories(pending); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExportProducts streams the whole catalog, inactive products included, in
+// batches so memory use does not grow with the catalog.
+func (s *catalogService) ExportProducts(format string, w io.Writer) error {
+	categories, err := s.repo.GetAllCategories(nil)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+
+	enc := newCatalogEncoder(format, w, catalogProductColumns)
+	err = s.repo.ProductsInBatches(catalogExportBatch, func(batch []store.Product) error {
+		for i := range batch {
+			p := &batch[i]
+			var tags []string
+			if err := json.Unmarshal(p.Tags, &tags); err != nil {
+				tags = []string{}
+			}
+			sku := ""
+			if p.ExternalSKU != nil {
+				sku = *p.ExternalSKU
+			}
+			active := p.IsActive
+			row := types.CatalogProductRow{
+				ExternalSKU:      sku,
+				Name:             p.Name,
+				Description:      p.Description,
+				Category:         names[p.CategoryID],
+				RedemptionPoints: p.RedemptionPoints,
+				StockQuantity:    p.StockQuantity,
+				IsOffer:          p.IsOffer,
+				IsActive:         &active,
+				Tags:             tags,
+				ImageURL:         p.ImageURL,
+			}
+			record := []string{
+				row.ExternalSKU, row.Name, row.Description, row.Category,
+				strconv.Itoa(row.RedemptionPoints), strconv.Itoa(row.StockQuantity),
+				strconv.FormatBool(row.IsOffer), strconv.FormatBool(active),
+				strings.Join(tags, "|"), row.ImageURL,
+			}
+			if err := enc.write(row, record); err != nil {
+				return err
+			}
+		}
+		return enc.flush()
+	})
+	if err != nil {
+		return err
+	}
+	return enc.close()
+}
+
+func (s *catalogService) ExportCategories(format string, w io.Writer) error {
+	categories, err := s.repo.GetAllCategories(nil)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+
+	enc := newCatalogEncoder(format, w, catalogCategoryColumns)
+	for _, c := range orderParentsFirst(categories) {
+		row := types.CatalogCategoryRow{Name: c.Name, Description: c.Description}
+		if c.ParentCategoryID != nil {
+			row.Parent = names[*c.ParentCategoryID]
+		}
+		if err := enc.write(row, []string{row.Name, row.Description, row.Parent}); err != nil {
+			return err
+		}
+	}
+	return enc.close()
+}
+
+// orderParentsFirst sorts categories so that every parent precedes its
+// children, which lets an export be imported again as is.
+func orderParentsFirst(categories []store.Category) []store.Category {
+	children := map[string][]store.Category{}
+	known := map[string]bool{}
+	for _, c := range categories {
+		known[c.ID] = true
+	}
+	var roots []store.Category
+	for _, c := range categories {
+		if c.ParentCategoryID == nil || !known[*c.ParentCategoryID] {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentCategoryID] = append(children[*c.ParentCategoryID], c)
+		}
+	}
+
+	ordered := make([]store.Category, 0, len(categories))
+	visited := map[string]bool{}
+	var walk func(c store.Category)
+	walk = func(c store.Category) {
+		if visited[c.ID] {
+			return
+		}
+		visited[c.ID] = true
+		ordered = append(ordered, c)
+		for _, child := range children[c.ID] {
+			walk(child)
+		}
+	}
+	for _, c := range roots {
+		walk(c)
+	}
+	return ordered
+}
+
+func normalizeTags(raw []string) ([]string, []string) {
+	tags := []string{}
+	seen := map[string]bool{}
+	var errs []string
+	for _, t := range raw {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[strings.ToLower(t)] {
+			continue
+		}
+		if len(t) > catalogMaxTagLength {
+			errs = append(errs, fmt.Sprintf("tag %q is longer than %d characters", t, catalogMaxTagLength))
+			continue
+		}
+		seen[strings.ToLower(t)] = true
+		tags = append(tags, t)
+	}
+	if len(tags) > catalogMaxTags {
+		errs = append(errs, fmt.Sprintf("at most %d tags are allowed", catalogMaxTags))
+	}
+	return tags, errs
+}
+
+type categoryLookup struct {
+	byID     map[string]*store.Category
+	byLower  map[string][]*store.Category
+	parentOf map[string]string
+}
+
+func newCategoryLookup(categories []store.Category) *categoryLookup {
+	l := &categoryLookup{
+		byID:     map[string]*store.Category{},
+		byLower:  map[string][]*store.Category{},
+		parentOf: map[string]string{},
+	}
+	for i := range categories {
+		l.add(&categories[i])
+	}
+	return l
+}
+
+func (l *categoryLookup) add(c *store.Category) {
+	if _, exists := l.byID[c.ID]; !exists {
+		key := strings.ToLower(c.Name)
+		l.byLower[key] = append(l.byLower[key], c)
+	}
+	l.byID[c.ID] = c
+	delete(l.parentOf, c.ID)
+	if c.ParentCategoryID != nil {
+		l.parentOf[c.ID] = *c.ParentCategoryID
+	}
+}
+
+// resolve finds a category by ID or, failing that, by case-insensitive name.
+func (l *categoryLookup) resolve(ref string) (*store.Category, string) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, "category is required"
+	}
+	if c, ok := l.byID[ref]; ok {
+		return c, ""
+	}
+	c, errMsg := l.byName(ref)
+	if errMsg == "" && c == nil {
+		return nil, fmt.Sprintf("category %q not found", ref)
+	}
+	return c, errMsg
+}
+
+func (l *categoryLookup) byName(name string) (*store.Category, string) {
+	matches := l.byLower[strings.ToLower(strings.TrimSpace(name))]
+	switch len(matches) {
+	case 0:
+		return nil, ""
+	case 1:
+		return matches[0], ""
+	default:
+		return nil, fmt.Sprintf("category name %q is ambiguous, use the category ID", name)
+	}
+}
+
+func (l *categoryLookup) isSelfOrDescendant(id, ancestorID string) bool {
+	visited := map[string]bool{}
+	for id != "" && !visited[id] {
+		if id == ancestorID {
+			return true
+		}
+		visited[id] = true
+		id = l.parentOf[id]
+	}
+	return false
+}
+
+func parseProductRows(format string, body io.Reader) ([]productImportRow, error) {
+	if format == "json" {
+		var rows []types.CatalogProductRow
+		if err := json.NewDecoder(body).Decode(&rows); err != nil {
+			return nil, errors.New("invalid JSON")
+		}
+		out := make([]productImportRow, len(rows))
+		for i, r := range rows {
+			out[i] = productImportRow{line: i + 1, row: r}
+		}
+		return out, nil
+	}
+
+	records, columns, err := readCSV(body, "external_sku", "name", "category", "redemption_points")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]productImportRow, 0, len(records))
+	for i, rec := range records {
+		get := func(col string) string {
+			if idx, ok := columns[col]; ok && idx < len(rec) {
+				return strings.TrimSpace(rec[idx])
+			}
+			return ""
+		}
+
+		r := productImportRow{line: i + 2}
+		r.row = types.CatalogProductRow{
+			ExternalSKU: get("external_sku"),
+			Name:        get("name"),
+			Description: get("description"),
+			Category:    get("category"),
+			ImageURL:    get("image_url"),
+		}
+		if v := get("tags"); v != "" {
+			r.row.Tags = strings.Split(v, "|")
+		}
+		if v := get("redemption_points"); v != "" {
+			if r.row.RedemptionPoints, err = strconv.Atoi(v); err != nil {
+				r.errs = append(r.errs, "redemption_points must be a whole number")
+			}
+		}
+		if v := get("stock_quantity"); v != "" {
+			if r.row.StockQuantity, err = strconv.Atoi(v); err != nil {
+				r.errs = append(r.errs, "stock_quantity must be a whole number")
+			}
+		}
+		if v := get("is_offer"); v != "" {
+			if r.row.IsOffer, err = strconv.ParseBool(v); err != nil {
+				r.errs = append(r.errs, "is_offer must be true or false")
+			}
+		}
+		if v := get("is_active"); v != "" {
+			active, err := strconv.ParseBool(v)
+			if err != nil {
+				r.errs = append(r.errs, "is_active must be true or false")
+			} else {
+				r.row.IsActive = &active
+			}
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func parseCategoryRows(format string, body io.Reader) ([]categoryImportRow, error) {
+	if format == "json" {
+		var rows []types.CatalogCategoryRow
+		if err := json.NewDecoder(body).Decode(&rows); err != nil {
+			return nil, errors.New("invalid JSON")
+		}
+		out := make([]categoryImportRow, len(rows))
+		for i, r := range rows {
+			out[i] = categoryImportRow{line: i + 1, row: r}
+		}
+		return out, nil
+	}
+
+	records, columns, err := readCSV(body, "name")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]categoryImportRow, 0, len(records))
+	for i, rec := range records {
+		get := func(col string) string {
+			if idx, ok := columns[col]; ok && idx < len(rec) {
+				return strings.TrimSpace(rec[idx])
+			}
+			return ""
+		}
+		out = append(out, categoryImportRow{line: i + 2, row: types.CatalogCategoryRow{
+			Name:        get("name"),
+			Description: get("description"),
+			Parent:      get("parent"),
+		}})
+	}
+	return out, nil
+}
+
+// readCSV returns the data records and a column index built from the header
+// row, which must contain every required column.
+func readCSV(body io.Reader, required ...string) ([][]string, map[string]int, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.New("invalid CSV")
+	}
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, col := range required {
+		if _, ok := columns[col]; !ok {
+			return nil, nil, fmt.Errorf("missing column %s", col)
+		}
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, errors.New("invalid CSV")
+	}
+	return records, columns, nil
+}
+
+type catalogEncoder struct {
+	format string
+	w      io.Writer
+	csv    *csv.Writer
+	count  int
+}
+
+func newCatalogEncoder(format string, w io.Writer, header []string) *catalogEncoder {
+	e := &catalogEncoder{format: format, w: w}
+	if format == "json" {
+		_, _ = io.WriteString(w, "[")
+	} else {
+		e.csv = csv.NewWriter(w)
+		_ = e.csv.Write(header)
+	}
+	return e
+}
+
+func (e *catalogEncoder) write(row interface{}, record []string) error {
+	if e.csv != nil {
+		return e.csv.Write(record)
+	}
+
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "\n"
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *catalogEncoder) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+	return nil
+}
+
+func (e *catalogEncoder) close() error {
+	if e.csv != nil {
+		return e.flush()
+	}
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
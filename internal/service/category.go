@@ -18,10 +18,17 @@ func NewCategoryService(repo *repository.Repository) CategoryService {
 }
 
 func (s *categoryService) CreateCategory(c *types.CreateCategoryRequest) (*types.CategoryResponse, error) {
+	translations, err := mergeTranslations(nil, c.Translations)
+	if err != nil {
+		return nil, err
+	}
+
 	newCategory := &store.Category{
-		ID:          uuid.NewString(),
-		Name:        c.Name,
-		Description: c.Description,
+		ID:           uuid.NewString(),
+		Name:         c.Name,
+		Description:  c.Description,
+		IsActive:     true,
+		Translations: translations,
 	}
 
 	if c.ParentCategoryID != "" {
@@ -44,27 +51,74 @@ func (s *categoryService) CreateCategory(c *types.CreateCategoryRequest) (*types
 		Name:             newCategory.Name,
 		Description:      newCategory.Description,
 		ParentCategoryID: newCategory.ParentCategoryID,
+		Translations:     decodeTranslations(newCategory.Translations),
 	}, nil
 }
 
-func (s *categoryService) GetAllCategories(parentID *string) ([]store.Category, error) {
-	return s.repo.GetAllCategories(parentID)
+func (s *categoryService) GetAllCategories(parentID *string, locale string) ([]store.Category, error) {
+	categories, err := s.repo.GetActiveCategories(parentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		localizeCategory(&categories[i], locale)
+	}
+	return categories, nil
+}
+
+func (s *categoryService) GetCategoryTree(locale string) ([]*types.CategoryTreeNode, error) {
+	rows, err := s.repo.FetchCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows come parents first, so every parent node exists before its children.
+	nodes := make(map[string]*types.CategoryTreeNode, len(rows))
+	roots := []*types.CategoryTreeNode{}
+	for _, row := range rows {
+		name, description := localizedText(row.Translations, locale, row.Name, row.Description)
+		node := &types.CategoryTreeNode{
+			ID:                 row.ID,
+			Name:               name,
+			Description:        description,
+			ParentCategoryID:   row.ParentCategoryID,
+			ProductCount:       row.TotalCount,
+			DirectProductCount: row.DirectCount,
+			Children:           []*types.CategoryTreeNode{},
+		}
+		nodes[row.ID] = node
+		if row.ParentCategoryID == nil {
+			roots = append(roots, node)
+		} else if parent, ok := nodes[*row.ParentCategoryID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return roots, nil
 }
 
-func (s *categoryService) GetCategoryDetails(categoryID string, page, limit int) (*types.CategoryDetailsResponse, error) {
+func (s *categoryService) GetCategoryDetails(categoryID string, page, limit int, includeDescendants bool, locale string) (*types.CategoryDetailsResponse, error) {
 	cat, err := s.repo.GetCategoryByID(categoryID)
-	if err != nil || cat == nil {
+	if err != nil || cat == nil || !cat.IsActive {
 		return nil, errors.New("category not found")
 	}
+	localizeCategory(cat, locale)
+
+	categoryIDs := []string{cat.ID}
+	if includeDescendants {
+		if categoryIDs, err = s.repo.GetCategorySubtreeIDs(cat.ID); err != nil {
+			return nil, err
+		}
+	}
 
-	products, total, err := s.repo.GetProductsByCategoryID(categoryID, page, limit)
+	products, total, err := s.repo.GetProductsByCategoryIDs(categoryIDs, page, limit)
 	if err != nil {
 		return nil, err
 	}
 
 	var productResponses []*types.ProductResponse
 	for _, p := range products {
-		productResponses = append(productResponses, ToProductResponse(p, cat))
+		localizeProduct(p, locale)
+		productResponses = append(productResponses, ToProductResponse(p, &p.Category))
 	}
 
 	totalPages := (total + limit - 1) / limit
@@ -87,25 +141,37 @@ func (s *categoryService) UpdateCategory(id string, input *types.UpdateCategoryR
 		return nil, errors.New("category not found")
 	}
 
+	updates := map[string]interface{}{}
 	if input.Name != nil {
 		existing.Name = *input.Name
+		updates["name"] = existing.Name
 	}
 	if input.Description != nil {
 		existing.Description = *input.Description
+		updates["description"] = existing.Description
+	}
+	if input.Translations != nil {
+		if existing.Translations, err = mergeTranslations(existing.Translations, input.Translations); err != nil {
+			return nil, err
+		}
+		updates["translations"] = existing.Translations
 	}
 	if input.ParentCategoryID != nil {
+		existing.ParentCategoryID = nil
 		if *input.ParentCategoryID != "" {
-			parent, _ := s.repo.GetCategoryByID(*input.ParentCategoryID)
-			if parent == nil {
+			p, _ := s.repo.GetCategoryByID(*input.ParentCategoryID)
+			if p == nil || !p.IsActive {
 				return nil, errors.New("parent category not found")
 			}
-			existing.ParentCategoryID = input.ParentCategoryID
-		} else {
-			existing.ParentCategoryID = nil
+			existing.ParentCategoryID = &p.ID
 		}
+		updates["parent_category_id"] = existing.ParentCategoryID
 	}
 
-	if err := s.repo.UpdateCategory(existing); err != nil {
+	if err := s.repo.UpdateCategory(existing.ID, updates); err != nil {
+		if errors.Is(err, repository.ErrCategoryCycle) {
+			return nil, errors.New("category cycle")
+		}
 		return nil, err
 	}
 
@@ -114,9 +180,74 @@ func (s *categoryService) UpdateCategory(id string, input *types.UpdateCategoryR
 		Name:             existing.Name,
 		Description:      existing.Description,
 		ParentCategoryID: existing.ParentCategoryID,
+		Translations:     decodeTranslations(existing.Translations),
 	}, nil
 }
 
-func (s *categoryService) DeleteCategory(id string) error {
-	return s.repo.DeleteCategory(id)
+// DeleteCategory removes a category according to the strategy: "refuse" only
+// deletes empty categories, "reassign" moves its products and children to
+// targetID first, and "archive" deactivates the whole subtree with its
+// products instead of deleting anything.
+func (s *categoryService) DeleteCategory(id, strategy, targetID string) error {
+	cat, err := s.repo.GetCategoryByID(id)
+	if err != nil || cat == nil || !cat.IsActive {
+		return errors.New("category not found")
+	}
+
+	switch strategy {
+	case "", "refuse":
+		if err := s.repo.DeleteEmptyCategory(id); err != nil {
+			if errors.Is(err, repository.ErrCategoryNotEmpty) {
+				return errors.New("category is not empty")
+			}
+			return err
+		}
+		return nil
+	case "reassign":
+		target, _ := s.repo.GetCategoryByID(targetID)
+		if target == nil || !target.IsActive {
+			return errors.New("target category not found")
+		}
+		if err := s.repo.ReassignAndDeleteCategory(id, target.ID); err != nil {
+			if errors.Is(err, repository.ErrCategoryCycle) {
+				return errors.New("category cycle")
+			}
+			return err
+		}
+		return nil
+	case "archive":
+		return s.repo.ArchiveCategorySubtree(id)
+	}
+	return errors.New("invalid delete strategy")
+}
+
+func (s *categoryService) MoveCategory(id, parentID string) (*types.CategoryResponse, error) {
+	cat, err := s.repo.GetCategoryByID(id)
+	if err != nil || cat == nil || !cat.IsActive {
+		return nil, errors.New("category not found")
+	}
+
+	var parent *string
+	if parentID != "" {
+		p, _ := s.repo.GetCategoryByID(parentID)
+		if p == nil || !p.IsActive {
+			return nil, errors.New("parent category not found")
+		}
+		parent = &p.ID
+	}
+
+	if err := s.repo.MoveCategory(id, parent); err != nil {
+		if errors.Is(err, repository.ErrCategoryCycle) {
+			return nil, errors.New("category cycle")
+		}
+		return nil, err
+	}
+
+	return &types.CategoryResponse{
+		ID:               cat.ID,
+		Name:             cat.Name,
+		Description:      cat.Description,
+		ParentCategoryID: parent,
+		Translations:     decodeTranslations(cat.Translations),
+	}, nil
 }
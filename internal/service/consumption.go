@@ -0,0 +1,450 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHoldTTL = time.Hour
+	maxHoldTTL     = 24 * time.Hour
+
+	maxConsumptionQuantity = 1_000_000
+)
+
+var actionKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+type consumptionService struct {
+	repo *repository.Repository
+}
+
+func NewConsumptionService(repo *repository.Repository) ConsumptionService {
+	return &consumptionService{repo: repo}
+}
+
+func (s *consumptionService) GetActions(includeInactive bool) ([]types.BillableActionResponse, error) {
+	actions, err := s.repo.ListBillableActions(!includeInactive)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]types.BillableActionResponse, 0, len(actions))
+	for i := range actions {
+		res = append(res, *toBillableActionResponse(&actions[i]))
+	}
+	return res, nil
+}
+
+func (s *consumptionService) CreateAction(input *types.CreateBillableActionRequest) (*types.BillableActionResponse, error) {
+	key := strings.ToLower(strings.TrimSpace(input.Key))
+	if !actionKeyPattern.MatchString(key) {
+		return nil, errors.New("invalid action key")
+	}
+	existing, err := s.repo.GetBillableActionByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("action already exists")
+	}
+
+	now := time.Now()
+	action := &store.BillableAction{
+		ID:             uuid.NewString(),
+		Key:            key,
+		Name:           input.Name,
+		Description:    input.Description,
+		CreditsPerUnit: input.CreditsPerUnit,
+		IsActive:       input.IsActive == nil || *input.IsActive,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.repo.CreateBillableAction(action); err != nil {
+		return nil, err
+	}
+	return toBillableActionResponse(action), nil
+}
+
+// UpdateAction changes the price list entry. The key is what products bill
+// against, so it cannot be changed; open holds keep the price they were
+// placed at.
+func (s *consumptionService) UpdateAction(id string, input *types.UpdateBillableActionRequest) (*types.BillableActionResponse, error) {
+	action, err := s.repo.GetBillableActionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if action == nil {
+		return nil, errors.New("action not found")
+	}
+
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return nil, errors.New("invalid action")
+		}
+		action.Name = *input.Name
+	}
+	if input.Description != nil {
+		action.Description = *input.Description
+	}
+	if input.CreditsPerUnit != nil {
+		if *input.CreditsPerUnit <= 0 {
+			return nil, errors.New("invalid action")
+		}
+		action.CreditsPerUnit = *input.CreditsPerUnit
+	}
+	if input.IsActive != nil {
+		action.IsActive = *input.IsActive
+	}
+	action.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateBillableAction(action); err != nil {
+		return nil, err
+	}
+	return toBillableActionResponse(action), nil
+}
+
+// Consume bills the user for a completed action in one step.
+func (s *consumptionService) Consume(apiKeyID, userID string, input *types.ConsumeCreditsRequest) (*types.CreditUsageResponse, error) {
+	action, quantity, credits, err := s.prepareCharge(apiKeyID, userID, input.Action, input.Quantity, input.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &store.CreditUsage{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		ActionKey: action.Key,
+		Quantity:  quantity,
+		Credits:   credits,
+		APIKeyID:  &apiKeyID,
+		Reference: input.Reference,
+		CreatedAt: time.Now(),
+	}
+	err = s.repo.WithTx(func(tx *gorm.DB) error {
+		if err := s.repo.ApplyWalletChangeTx(tx, &store.WalletTransaction{
+			UserID:       userID,
+			CreditsDelta: -usage.Credits,
+			Reason:       "consumption",
+			ReferenceID:  usage.ID,
+			Note:         action.Key,
+			APIKeyID:     &apiKeyID,
+		}); err != nil {
+			return err
+		}
+		return s.repo.CreateCreditUsageTx(tx, usage)
+	})
+	if errors.Is(err, repository.ErrInsufficientBalance) {
+		return nil, errors.New("insufficient credits")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.CreditUsageResponse{
+		ID:             usage.ID,
+		UserID:         usage.UserID,
+		Action:         usage.ActionKey,
+		Quantity:       usage.Quantity,
+		Credits:        usage.Credits,
+		Reference:      usage.Reference,
+		CreditsBalance: s.creditsBalance(userID),
+		CreatedAt:      usage.CreatedAt.Format(time.RFC3339),
+	}
+	return res, nil
+}
+
+// Hold reserves credits for the estimated quantity at the current price. The
+// credits leave the wallet straight away so they cannot be spent twice, and
+// come back when the hold is released, expires or is captured for less.
+func (s *consumptionService) Hold(apiKeyID, userID string, input *types.CreditHoldRequest) (*types.CreditHoldResponse, error) {
+	action, quantity, credits, err := s.prepareCharge(apiKeyID, userID, input.Action, input.Quantity, input.Reference)
+	if err != nil {
+		return nil, err
+	}
+	ttl := defaultHoldTTL
+	if input.TTLSeconds != 0 {
+		ttl = time.Duration(input.TTLSeconds) * time.Second
+		if ttl < 0 || ttl > maxHoldTTL {
+			return nil, errors.New("invalid ttl")
+		}
+	}
+
+	now := time.Now()
+	hold := &store.CreditHold{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		ActionKey:   action.Key,
+		APIKeyID:    &apiKeyID,
+		Reference:   input.Reference,
+		Quantity:    quantity,
+		UnitCredits: action.CreditsPerUnit,
+		CreditsHeld: credits,
+		Status:      "held",
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+	}
+	err = s.repo.WithTx(func(tx *gorm.DB) error {
+		if err := s.repo.ApplyWalletChangeTx(tx, &store.WalletTransaction{
+			UserID:       userID,
+			CreditsDelta: -hold.CreditsHeld,
+			Reason:       "consumption_hold",
+			ReferenceID:  hold.ID,
+			Note:         action.Key,
+			APIKeyID:     &apiKeyID,
+		}); err != nil {
+			return err
+		}
+		return s.repo.CreateCreditHoldTx(tx, hold)
+	})
+	if errors.Is(err, repository.ErrInsufficientBalance) {
+		return nil, errors.New("insufficient credits")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.toCreditHoldResponse(hold), nil
+}
+
+// CaptureHold bills the quantity actually used, which defaults to the held
+// quantity and cannot exceed it, and returns the rest of the hold.
+func (s *consumptionService) CaptureHold(apiKeyID, holdID string, quantity *int) (*types.CreditHoldResponse, error) {
+	return s.settleHold(apiKeyID, holdID, func(tx *gorm.DB, hold *store.CreditHold, now time.Time) error {
+		if now.After(hold.ExpiresAt) {
+			return errors.New("hold expired")
+		}
+		used := hold.Quantity
+		if quantity != nil {
+			used = *quantity
+		}
+		if used < 0 || used > hold.Quantity {
+			return errors.New("invalid quantity")
+		}
+
+		hold.Status = "captured"
+		hold.CreditsCaptured = used * hold.UnitCredits
+		if used > 0 {
+			if err := s.repo.CreateCreditUsageTx(tx, &store.CreditUsage{
+				ID:        uuid.NewString(),
+				UserID:    hold.UserID,
+				ActionKey: hold.ActionKey,
+				Quantity:  used,
+				Credits:   hold.CreditsCaptured,
+				HoldID:    &hold.ID,
+				APIKeyID:  hold.APIKeyID,
+				Reference: hold.Reference,
+				CreatedAt: now,
+			}); err != nil {
+				return err
+			}
+		}
+		return s.refundHoldTx(tx, hold, hold.CreditsHeld-hold.CreditsCaptured)
+	})
+}
+
+func (s *consumptionService) ReleaseHold(apiKeyID, holdID string) (*types.CreditHoldResponse, error) {
+	return s.settleHold(apiKeyID, holdID, func(tx *gorm.DB, hold *store.CreditHold, now time.Time) error {
+		hold.Status = "released"
+		return s.refundHoldTx(tx, hold, hold.CreditsHeld)
+	})
+}
+
+// ReleaseExpiredHolds returns the credits of holds that were neither captured
+// nor released before they expired.
+func (s *consumptionService) ReleaseExpiredHolds(now time.Time) (int, error) {
+	released := 0
+	for {
+		found := false
+		err := s.repo.WithTx(func(tx *gorm.DB) error {
+			hold, err := s.repo.ClaimExpiredHoldTx(tx, now)
+			if err != nil || hold == nil {
+				return err
+			}
+			found = true
+			hold.Status = "expired"
+			hold.SettledAt = &now
+			if err := s.refundHoldTx(tx, hold, hold.CreditsHeld); err != nil {
+				return err
+			}
+			return tx.Save(hold).Error
+		})
+		if err != nil {
+			return released, err
+		}
+		if !found {
+			return released, nil
+		}
+		released++
+	}
+}
+
+func (s *consumptionService) GetUsageReport(userID, dateFrom, dateTo string) (*types.UsageReport, error) {
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	rows, err := s.repo.UsageReport(userID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	report := &types.UsageReport{
+		UserID:   userID,
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+		Actions:  make([]types.UsageReportItem, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.TotalCredits += row.Credits
+		report.Actions = append(report.Actions, types.UsageReportItem{
+			Action:     row.ActionKey,
+			Name:       row.Name,
+			Events:     row.Events,
+			Quantity:   row.Quantity,
+			Credits:    row.Credits,
+			LastUsedAt: row.LastUsedAt.Format(time.RFC3339),
+		})
+	}
+	return report, nil
+}
+
+// prepareCharge validates a consume or hold request and returns the action,
+// the quantity to bill, which defaults to one unit and is capped at
+// maxConsumptionQuantity, and the credits it costs.
+func (s *consumptionService) prepareCharge(apiKeyID, userID, actionKey string, quantity int, reference string) (*store.BillableAction, int, int, error) {
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 || quantity > maxConsumptionQuantity {
+		return nil, 0, 0, errors.New("invalid quantity")
+	}
+
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if user == nil {
+		return nil, 0, 0, errors.New("user not found")
+	}
+
+	action, err := s.repo.GetBillableActionByKey(strings.ToLower(strings.TrimSpace(actionKey)))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if action == nil || !action.IsActive {
+		return nil, 0, 0, errors.New("action not found")
+	}
+
+	// The price is checked against the quantity before multiplying so a
+	// large request cannot wrap around into a negative charge.
+	if action.CreditsPerUnit > 0 && quantity > math.MaxInt/action.CreditsPerUnit {
+		return nil, 0, 0, errors.New("invalid quantity")
+	}
+
+	// Concurrent calls with the same reference can both pass this check; the
+	// unique reference indexes stop the second one when it is saved.
+	if reference != "" {
+		used, err := s.repo.ConsumptionReferenceUsed(apiKeyID, reference)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if used {
+			return nil, 0, 0, errors.New("duplicate reference")
+		}
+	}
+	return action, quantity, action.CreditsPerUnit * quantity, nil
+}
+
+// settleHold locks an open hold placed by the API key and applies fn to it.
+func (s *consumptionService) settleHold(apiKeyID, holdID string, fn func(tx *gorm.DB, hold *store.CreditHold, now time.Time) error) (*types.CreditHoldResponse, error) {
+	var hold *store.CreditHold
+	err := s.repo.WithTx(func(tx *gorm.DB) error {
+		var err error
+		hold, err = s.repo.LockCreditHoldTx(tx, holdID)
+		if err != nil {
+			return err
+		}
+		if hold == nil || hold.APIKeyID == nil || *hold.APIKeyID != apiKeyID {
+			return errors.New("hold not found")
+		}
+		if hold.Status != "held" {
+			return errors.New("hold already settled")
+		}
+
+		now := time.Now()
+		if err := fn(tx, hold, now); err != nil {
+			return err
+		}
+		hold.SettledAt = &now
+		return tx.Save(hold).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toCreditHoldResponse(hold), nil
+}
+
+func (s *consumptionService) refundHoldTx(tx *gorm.DB, hold *store.CreditHold, credits int) error {
+	if credits <= 0 {
+		return nil
+	}
+	return s.repo.ApplyWalletChangeTx(tx, &store.WalletTransaction{
+		UserID:       hold.UserID,
+		CreditsDelta: credits,
+		Reason:       "consumption_release",
+		ReferenceID:  hold.ID,
+		Note:         hold.ActionKey,
+		APIKeyID:     hold.APIKeyID,
+	})
+}
+
+func (s *consumptionService) creditsBalance(userID string) int {
+	wallet, err := s.repo.GetWalletByUserID(userID)
+	if err != nil {
+		return 0
+	}
+	return wallet.CreditsBalance
+}
+
+func (s *consumptionService) toCreditHoldResponse(hold *store.CreditHold) *types.CreditHoldResponse {
+	res := &types.CreditHoldResponse{
+		ID:              hold.ID,
+		UserID:          hold.UserID,
+		Action:          hold.ActionKey,
+		Quantity:        hold.Quantity,
+		UnitCredits:     hold.UnitCredits,
+		CreditsHeld:     hold.CreditsHeld,
+		CreditsCaptured: hold.CreditsCaptured,
+		Status:          hold.Status,
+		Reference:       hold.Reference,
+		CreditsBalance:  s.creditsBalance(hold.UserID),
+		ExpiresAt:       hold.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:       hold.CreatedAt.Format(time.RFC3339),
+	}
+	if hold.SettledAt != nil {
+		settled := hold.SettledAt.Format(time.RFC3339)
+		res.SettledAt = &settled
+	}
+	return res
+}
+
+func toBillableActionResponse(a *store.BillableAction) *types.BillableActionResponse {
+	return &types.BillableActionResponse{
+		ID:             a.ID,
+		Key:            a.Key,
+		Name:           a.Name,
+		Description:    a.Description,
+		CreditsPerUnit: a.CreditsPerUnit,
+		IsActive:       a.IsActive,
+		UpdatedAt:      a.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"Start/internal/repository"
+	"Start/internal/shared/utils"
 	"Start/internal/store"
 	"Start/internal/types"
 	"errors"
@@ -17,7 +18,7 @@ func NewCreditPackageService(repo *repository.Repository) CreditPackageService {
 	return &creditPackageService{repo: repo}
 }
 
-func (s *creditPackageService) GetAllCreditPackages(page, limit int, activeFilter *bool) ([]types.CreditCreditPackageResponse, types.PaginationMeta, error) {
+func (s *creditPackageService) GetAllCreditPackages(page, limit int, activeFilter *bool, locale string) ([]types.CreditCreditPackageResponse, types.PaginationMeta, error) {
 	results, total, err := s.repo.GetPaginatedPackages(page, limit, activeFilter)
 	if err != nil {
 		return nil, types.PaginationMeta{}, err
@@ -26,15 +27,7 @@ func (s *creditPackageService) GetAllCreditPackages(page, limit int, activeFilte
 	totalPages := (int(total) + limit - 1) / limit
 	var res []types.CreditCreditPackageResponse
 	for _, p := range results {
-		res = append(res, types.CreditCreditPackageResponse{
-			ID:           p.ID,
-			Name:         p.Name,
-			PriceEGP:     p.PriceEGP,
-			Credits:      p.Credits,
-			RewardPoints: p.RewardPoints,
-			IsActive:     p.IsActive,
-			CreatedAt:    p.CreatedAt.Format(time.RFC3339),
-		})
+		res = append(res, *toCreditPackageResponse(&p, locale))
 	}
 
 	return res, types.PaginationMeta{
@@ -45,23 +38,20 @@ func (s *creditPackageService) GetAllCreditPackages(page, limit int, activeFilte
 	}, nil
 }
 
-func (s *creditPackageService) GetCreditPackageByID(id string) (*types.CreditCreditPackageResponse, error) {
+func (s *creditPackageService) GetCreditPackageByID(id, locale string) (*types.CreditCreditPackageResponse, error) {
 	pkg, err := s.repo.GetCreditPackageByID(id)
 	if err != nil {
 		return nil, err
 	}
-	return &types.CreditCreditPackageResponse{
-		ID:           pkg.ID,
-		Name:         pkg.Name,
-		PriceEGP:     pkg.PriceEGP,
-		Credits:      pkg.Credits,
-		RewardPoints: pkg.RewardPoints,
-		IsActive:     pkg.IsActive,
-		CreatedAt:    pkg.CreatedAt.Format(time.RFC3339),
-	}, nil
+	return toCreditPackageResponse(pkg, locale), nil
 }
 
-func (s *creditPackageService) CreateCreditPackage(input types.CreateCreditPackageRequest) (*types.CreditCreditPackageResponse, error) {
+func (s *creditPackageService) CreateCreditPackage(actorID string, input types.CreateCreditPackageRequest) (*types.CreditCreditPackageResponse, error) {
+	translations, err := mergeTranslations(nil, input.Translations)
+	if err != nil {
+		return nil, err
+	}
+
 	pkg := &store.CreditPackage{
 		ID:           uuid.NewString(),
 		Name:         input.Name,
@@ -70,24 +60,20 @@ func (s *creditPackageService) CreateCreditPackage(input types.CreateCreditPacka
 		RewardPoints: input.RewardPoints,
 		IsActive:     input.IsActive,
 		CreatedAt:    time.Now(),
+		Translations: translations,
 	}
 
-	if err := s.repo.CreateCreditPackage(pkg); err != nil {
+	if err := s.repo.CreateCreditPackage(pkg, actorIDPtr(actorID)); err != nil {
 		return nil, err
 	}
 
-	return &types.CreditCreditPackageResponse{
-		ID:           pkg.ID,
-		Name:         pkg.Name,
-		PriceEGP:     pkg.PriceEGP,
-		Credits:      pkg.Credits,
-		RewardPoints: pkg.RewardPoints,
-		IsActive:     pkg.IsActive,
-		CreatedAt:    pkg.CreatedAt.Format(time.RFC3339),
-	}, nil
+	return toCreditPackageResponse(pkg, utils.DefaultLocale), nil
 }
 
-func (s *creditPackageService) UpdateCreditPackages(id string, input types.UpdateCreditPackageRequest) (*types.CreditCreditPackageResponse, error) {
+func (s *creditPackageService) UpdateCreditPackages(actorID, id string, input types.UpdateCreditPackageRequest) (*types.CreditCreditPackageResponse, error) {
+	if err := s.repo.ApplyDuePriceChanges(); err != nil {
+		return nil, err
+	}
 	pkg, err := s.repo.GetCreditPackageByID(id)
 	if err != nil {
 		return nil, errors.New("package not found")
@@ -98,22 +84,33 @@ func (s *creditPackageService) UpdateCreditPackages(id string, input types.Updat
 	pkg.Credits = input.Credits
 	pkg.RewardPoints = input.RewardPoints
 	pkg.IsActive = input.IsActive
+	if input.Translations != nil {
+		if pkg.Translations, err = mergeTranslations(pkg.Translations, input.Translations); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.repo.UpdateCreditPackage(pkg); err != nil {
+	if err := s.repo.UpdateCreditPackage(pkg, actorIDPtr(actorID)); err != nil {
 		return nil, err
 	}
 
+	return toCreditPackageResponse(pkg, utils.DefaultLocale), nil
+}
+
+func (s *creditPackageService) DeleteCreditPackage(id string) error {
+	return s.repo.DeleteCreditPackage(id)
+}
+
+func toCreditPackageResponse(pkg *store.CreditPackage, locale string) *types.CreditCreditPackageResponse {
+	name, _ := localizedText(pkg.Translations, locale, pkg.Name, "")
 	return &types.CreditCreditPackageResponse{
 		ID:           pkg.ID,
-		Name:         pkg.Name,
+		Name:         name,
 		PriceEGP:     pkg.PriceEGP,
 		Credits:      pkg.Credits,
 		RewardPoints: pkg.RewardPoints,
 		IsActive:     pkg.IsActive,
 		CreatedAt:    pkg.CreatedAt.Format(time.RFC3339),
-	}, nil
-}
-
-func (s *creditPackageService) DeleteCreditPackage(id string) error {
-	return s.repo.DeleteCreditPackage(id)
+		Translations: decodeTranslations(pkg.Translations),
+	}
 }
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+func (s *creditPackageService) GetPriceHistory(id string) ([]types.PriceHistoryEntry, error) {
+	if _, err := s.repo.GetCreditPackageByID(id); err != nil {
+		return nil, errors.New("package not found")
+	}
+
+	history, err := s.repo.ListPackagePriceHistory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]types.PriceHistoryEntry, 0, len(history))
+	for _, h := range history {
+		entry := types.PriceHistoryEntry{
+			ID:            h.ID,
+			PriceEGP:      h.PriceEGP,
+			Credits:       h.Credits,
+			RewardPoints:  h.RewardPoints,
+			EffectiveFrom: h.EffectiveFrom.Format(time.RFC3339),
+			ChangedBy:     h.ChangedBy,
+			PriceChangeID: h.PriceChangeID,
+		}
+		if h.EffectiveTo != nil {
+			to := h.EffectiveTo.Format(time.RFC3339)
+			entry.EffectiveTo = &to
+		}
+		res = append(res, entry)
+	}
+	return res, nil
+}
+
+func (s *creditPackageService) SchedulePriceChange(actorID, id string, input *types.ScheduledPriceChangeRequest) (*types.PriceChangeResponse, error) {
+	pkg, err := s.repo.GetCreditPackageByID(id)
+	if err != nil {
+		return nil, errors.New("package not found")
+	}
+	if input.PriceEGP <= 0 || input.Credits <= 0 || input.RewardPoints < 0 {
+		return nil, errors.New("invalid pricing")
+	}
+	if !input.EffectiveAt.After(time.Now()) {
+		return nil, errors.New("effective time must be in the future")
+	}
+
+	change := &store.CreditPackagePriceChange{
+		ID:              uuid.NewString(),
+		CreditPackageID: pkg.ID,
+		PriceEGP:        input.PriceEGP,
+		Credits:         input.Credits,
+		RewardPoints:    input.RewardPoints,
+		EffectiveAt:     input.EffectiveAt,
+		Status:          "pending",
+		CreatedBy:       actorIDPtr(actorID),
+		CreatedAt:       time.Now(),
+	}
+	if err := s.repo.CreatePriceChange(change); err != nil {
+		return nil, err
+	}
+	return toPriceChangeResponse(change), nil
+}
+
+func (s *creditPackageService) GetPriceChanges(id, status string) ([]types.PriceChangeResponse, error) {
+	if _, err := s.repo.GetCreditPackageByID(id); err != nil {
+		return nil, errors.New("package not found")
+	}
+
+	changes, err := s.repo.ListPriceChanges(id, status)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]types.PriceChangeResponse, 0, len(changes))
+	for i := range changes {
+		res = append(res, *toPriceChangeResponse(&changes[i]))
+	}
+	return res, nil
+}
+
+func (s *creditPackageService) CancelPriceChange(id, changeID string) error {
+	if err := s.repo.ApplyDuePriceChanges(); err != nil {
+		return err
+	}
+	found, err := s.repo.CancelPriceChange(id, changeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("price change not found")
+	}
+	return nil
+}
+
+func toPriceChangeResponse(c *store.CreditPackagePriceChange) *types.PriceChangeResponse {
+	res := &types.PriceChangeResponse{
+		ID:              c.ID,
+		CreditPackageID: c.CreditPackageID,
+		PriceEGP:        c.PriceEGP,
+		Credits:         c.Credits,
+		RewardPoints:    c.RewardPoints,
+		EffectiveAt:     c.EffectiveAt.Format(time.RFC3339),
+		Status:          c.Status,
+		CreatedBy:       c.CreatedBy,
+		CreatedAt:       c.CreatedAt.Format(time.RFC3339),
+	}
+	if c.AppliedAt != nil {
+		appliedAt := c.AppliedAt.Format(time.RFC3339)
+		res.AppliedAt = &appliedAt
+	}
+	return res
+}
@@ -4,14 +4,20 @@ import (
 	"Start/internal/store"
 	"Start/internal/types"
 	"gorm.io/gorm"
+	"io"
+	"time"
 )
 
 type CreditPackageService interface {
-	GetAllCreditPackages(page, limit int, activeFilter *bool) ([]types.CreditCreditPackageResponse, types.PaginationMeta, error)
-	GetCreditPackageByID(id string) (*types.CreditCreditPackageResponse, error)
-	CreateCreditPackage(input types.CreateCreditPackageRequest) (*types.CreditCreditPackageResponse, error)
-	UpdateCreditPackages(id string, input types.UpdateCreditPackageRequest) (*types.CreditCreditPackageResponse, error)
+	GetAllCreditPackages(page, limit int, activeFilter *bool, locale string) ([]types.CreditCreditPackageResponse, types.PaginationMeta, error)
+	GetCreditPackageByID(id, locale string) (*types.CreditCreditPackageResponse, error)
+	CreateCreditPackage(actorID string, input types.CreateCreditPackageRequest) (*types.CreditCreditPackageResponse, error)
+	UpdateCreditPackages(actorID, id string, input types.UpdateCreditPackageRequest) (*types.CreditCreditPackageResponse, error)
 	DeleteCreditPackage(id string) error
+	GetPriceHistory(id string) ([]types.PriceHistoryEntry, error)
+	SchedulePriceChange(actorID, id string, input *types.ScheduledPriceChangeRequest) (*types.PriceChangeResponse, error)
+	GetPriceChanges(id, status string) ([]types.PriceChangeResponse, error)
+	CancelPriceChange(id, changeID string) error
 }
 
 type PurchaseService interface {
@@ -23,32 +29,110 @@ type PurchaseService interface {
 }
 
 type ProductService interface {
-	GetAllProducts(filters types.ProductFilters, page, limit int, sortBy, sortOrder string) ([]store.Product, types.PaginationMeta, error)
-	SearchProducts(query string, filters types.ProductFilters, page, limit int) ([]store.Product, types.PaginationMeta, error)
-	CreateProduct(input *types.CreateProductRequest) (*types.ProductResponse, error)
-	UpdateProduct(id string, input *types.UpdateProductRequest) (*types.ProductResponse, error)
+	GetAllProducts(filters types.ProductFilters, page, limit int, sortBy, sortOrder, locale string) ([]store.Product, types.PaginationMeta, error)
+	SearchProducts(query, lang, locale string, filters types.ProductFilters, page, limit int) ([]types.ProductSearchHit, types.PaginationMeta, error)
+	SearchProductFacets(query, lang, locale string, filters types.ProductFilters) (*types.ProductFacets, error)
+	GetProductByID(id, locale string) (*types.ProductResponse, error)
+	CreateProduct(actorID string, input *types.CreateProductRequest) (*types.ProductResponse, error)
+	UpdateProduct(actorID, id string, input *types.UpdateProductRequest) (*types.ProductResponse, error)
 	DeleteProduct(id string) error
+	RestoreProduct(id string) (*types.ProductResponse, error)
+	CreateVariant(actorID, productID string, input *types.CreateVariantRequest) (*types.VariantResponse, error)
+	UpdateVariant(actorID, productID, variantID string, input *types.UpdateVariantRequest) (*types.VariantResponse, error)
+	DeleteVariant(productID, variantID string) error
+	AdjustStock(actorID, productID string, input *types.StockAdjustmentRequest) (*types.InventoryMovementResponse, error)
+	GetStockMovements(productID string, page, limit int) ([]types.InventoryMovementResponse, types.PaginationMeta, error)
+}
+
+type CatalogService interface {
+	ImportProducts(actorID, format string, body io.Reader, dryRun bool) (*types.ImportResult, error)
+	ImportCategories(format string, body io.Reader, dryRun bool) (*types.ImportResult, error)
+	ExportProducts(format string, w io.Writer) error
+	ExportCategories(format string, w io.Writer) error
+}
+
+type ProductImageService interface {
+	UploadImages(productID string, uploads []types.ImageUpload, makePrimary bool) ([]types.ProductImageResponse, error)
+	ListImages(productID string) ([]types.ProductImageResponse, error)
+	ReorderImages(productID string, imageIDs []string) ([]types.ProductImageResponse, error)
+	SetPrimaryImage(productID, imageID string) ([]types.ProductImageResponse, error)
+	DeleteImage(productID, imageID string) error
+}
+
+type ReviewService interface {
+	GetProductReviews(productID string, page, limit int) ([]types.ReviewResponse, types.PaginationMeta, error)
+	CreateReview(userID, productID string, input types.ReviewRequest) (*types.ReviewResponse, error)
+	UpdateReview(userID, productID, reviewID string, input types.ReviewRequest) (*types.ReviewResponse, error)
+	GetAllReviews(productID, status string, page, limit int) ([]types.ReviewResponse, types.PaginationMeta, error)
+	ModerateReview(adminID, reviewID string, input types.ModerateReviewRequest) (*types.ReviewResponse, error)
+}
+
+type WishlistService interface {
+	GetWishlist(userID string, page, limit int) ([]types.WishlistItemResponse, types.PaginationMeta, error)
+	AddToWishlist(userID, productID string) error
+	RemoveFromWishlist(userID, productID string) error
+	GetNotifications(userID string, unreadOnly bool, page, limit int) ([]types.NotificationResponse, types.PaginationMeta, error)
+	MarkNotificationRead(userID, id string) error
+}
+
+type SubscriptionService interface {
+	GetPlans() ([]types.SubscriptionPlanResponse, error)
+	CreatePlan(input *types.SubscriptionPlanRequest) (*types.SubscriptionPlanResponse, error)
+	UpdatePlan(id string, input *types.SubscriptionPlanRequest) (*types.SubscriptionPlanResponse, error)
+	GetSubscriptions(userID string) ([]types.SubscriptionResponse, error)
+	Subscribe(userID string, input *types.SubscribeRequest) (*types.SubscriptionResponse, error)
+	ChangePlan(userID, id, planID string) (*types.SubscriptionResponse, error)
+	Pause(userID, id string) (*types.SubscriptionResponse, error)
+	Resume(userID, id string) (*types.SubscriptionResponse, error)
+	Cancel(userID, id string, immediately bool) (*types.SubscriptionResponse, error)
+	ProcessDueRenewals(now time.Time) (int, error)
 }
 
 type CategoryService interface {
 	CreateCategory(c *types.CreateCategoryRequest) (*types.CategoryResponse, error)
-	GetAllCategories(parentID *string) ([]store.Category, error)
-	GetCategoryDetails(categoryID string, page, limit int) (*types.CategoryDetailsResponse, error)
+	GetAllCategories(parentID *string, locale string) ([]store.Category, error)
+	GetCategoryTree(locale string) ([]*types.CategoryTreeNode, error)
+	GetCategoryDetails(categoryID string, page, limit int, includeDescendants bool, locale string) (*types.CategoryDetailsResponse, error)
 	UpdateCategory(id string, input *types.UpdateCategoryRequest) (*types.CategoryResponse, error)
-	DeleteCategory(id string) error
+	DeleteCategory(id, strategy, targetID string) error
+	MoveCategory(id, parentID string) (*types.CategoryResponse, error)
 }
 
 type RedemptionService interface {
 	CreateRedemption(userID string, input types.CreateRedemptionRequest) (*types.RedemptionResponse, error)
 	GetRedemptionByID(userID, id string) (*types.RedemptionResponse, error)
 	GetUserRedemptions(userID string, page, limit int) ([]*types.RedemptionResponse, int64, error)
+	GetAllowance(userID, productID string) (*types.RedemptionAllowance, error)
+	GetPaymentSettings() (*types.RedemptionPaymentSettings, error)
+	UpdatePaymentSettings(adminID string, input types.RedemptionPaymentSettings) (*types.RedemptionPaymentSettings, error)
+}
+
+type CartService interface {
+	GetCart(userID string) (*types.CartResponse, error)
+	AddItem(userID string, input types.AddCartItemRequest) (*types.CartResponse, error)
+	UpdateItem(userID, itemID string, quantity int) (*types.CartResponse, error)
+	RemoveItem(userID, itemID string) (*types.CartResponse, error)
+	ClearCart(userID string) error
+	Checkout(userID string) (*types.RedemptionOrderResponse, error)
+	GetOrders(userID string, page, limit int) ([]types.RedemptionOrderResponse, types.PaginationMeta, error)
+	GetOrder(userID, id string) (*types.RedemptionOrderResponse, error)
 }
 
 type AuthService interface {
 	SignUp(input types.SignUpInput) (*store.User, error)
-	Login(input types.LoginInput) (*types.LoginResponse, error)
+	Login(input types.LoginInput, clientIP string) (*types.LoginResponse, error)
 	RefreshToken(refreshToken string) (*types.TokenPair, error)
 	ChangePassword(userID, currentPassword, newPassword string) error
+	CompleteMFALogin(input types.MFALoginInput, clientIP string) (*types.LoginResponse, error)
+	BeginTOTPEnrollment(userID string) (*types.TOTPEnrollmentResponse, error)
+	ConfirmTOTPEnrollment(userID, code string) (*types.TOTPActivationResponse, error)
+	DisableTOTP(userID, password, code string) error
+	RegenerateRecoveryCodes(userID, code string) ([]string, error)
+}
+
+type OIDCService interface {
+	StartLogin(provider string) (string, error)
+	CompleteLogin(provider, code, state string) (*types.LoginResponse, error)
 }
 
 type UserService interface {
@@ -66,10 +150,43 @@ type AdminService interface {
 	GetAllUsers(page, limit int, search, sortBy, sortOrder string) ([]*types.UserDTO, int, error)
 	GetAllPurchases(page, limit int, status, dateFrom, dateTo string) ([]*types.PurchaseResponse, int, error)
 	GetAllRedemptions(page, limit int, status, dateFrom, dateTo string) ([]*types.RedemptionResponse, int, error)
-	UpdateRedemptionStatus(id string, status string) error
-	ManageUserCredits(userID, action string, amount int) error
-	ManageUserPoints(userID, action string, amount int) error
+	UpdateRedemptionStatus(adminID, id, status, reason string) error
+	GetRedemptionQueue(adminID string, filters types.RedemptionQueueFilters, page, limit int) ([]types.RedemptionQueueItem, types.PaginationMeta, error)
+	ClaimRedemption(adminID, id string) (*types.RedemptionQueueItem, error)
+	AssignRedemption(adminID, id, assigneeID string) (*types.RedemptionQueueItem, error)
+	BulkUpdateRedemptions(adminID string, input types.BulkRedemptionRequest) ([]types.BulkRedemptionResult, error)
+	ManageUserCredits(adminID, userID, action string, amount int) error
+	ManageUserPoints(adminID, userID, action string, amount int) error
 	UpdateUserStatus(userID, status string) error
+	UnlockUser(userID string) error
+	GetStockForecast(windowDays, horizonDays int) ([]types.StockForecastItem, error)
+	GetNotifications(unreadOnly bool, page, limit int) ([]types.AdminNotificationResponse, int64, error)
+	MarkNotificationRead(id string) error
+	GetWishlistedOutOfStock(limit int) ([]types.WishlistDemandItem, error)
+}
+
+type APIKeyService interface {
+	CreateAPIKey(adminID string, input types.CreateAPIKeyRequest) (*types.CreatedAPIKeyResponse, error)
+	ListAPIKeys() ([]*types.APIKeyResponse, error)
+	RevokeAPIKey(id string) error
+	AuthenticateAPIKey(rawKey, clientIP, scope string) (string, error)
+}
+
+type IntegrationService interface {
+	AwardPoints(apiKeyID, userID string, input types.AwardPointsRequest) (*types.IntegrationWalletResponse, error)
+	GetWallet(userID string) (*types.IntegrationWalletResponse, error)
+}
+
+type ConsumptionService interface {
+	GetActions(includeInactive bool) ([]types.BillableActionResponse, error)
+	CreateAction(input *types.CreateBillableActionRequest) (*types.BillableActionResponse, error)
+	UpdateAction(id string, input *types.UpdateBillableActionRequest) (*types.BillableActionResponse, error)
+	Consume(apiKeyID, userID string, input *types.ConsumeCreditsRequest) (*types.CreditUsageResponse, error)
+	Hold(apiKeyID, userID string, input *types.CreditHoldRequest) (*types.CreditHoldResponse, error)
+	CaptureHold(apiKeyID, holdID string, quantity *int) (*types.CreditHoldResponse, error)
+	ReleaseHold(apiKeyID, holdID string) (*types.CreditHoldResponse, error)
+	ReleaseExpiredHolds(now time.Time) (int, error)
+	GetUsageReport(userID, dateFrom, dateTo string) (*types.UsageReport, error)
 }
 
 type AIService interface {
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"time"
+)
+
+type integrationService struct {
+	repo *repository.Repository
+}
+
+func NewIntegrationService(repo *repository.Repository) IntegrationService {
+	return &integrationService{repo: repo}
+}
+
+func (s *integrationService) AwardPoints(apiKeyID, userID string, input types.AwardPointsRequest) (*types.IntegrationWalletResponse, error) {
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// A concurrent call with the same reference passes this check but loses
+	// on the unique index, which ApplyWalletChange reports the same way.
+	if input.Reference != "" {
+		existing, err := s.repo.FindWalletTransactionByReference(apiKeyID, input.Reference)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, errors.New("duplicate reference")
+		}
+	}
+
+	if err := s.repo.ApplyWalletChange(&store.WalletTransaction{
+		UserID:      userID,
+		PointsDelta: input.Points,
+		Reason:      "integration_award",
+		ReferenceID: input.Reference,
+		Note:        input.Reason,
+		APIKeyID:    &apiKeyID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.GetWallet(userID)
+}
+
+func (s *integrationService) GetWallet(userID string) (*types.IntegrationWalletResponse, error) {
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	wallet, err := s.repo.GetWalletByUserID(userID)
+	if err != nil {
+		return &types.IntegrationWalletResponse{UserID: userID}, nil
+	}
+
+	return &types.IntegrationWalletResponse{
+		UserID:         wallet.UserID,
+		PointsBalance:  wallet.PointsBalance,
+		CreditsBalance: wallet.CreditsBalance,
+		UpdatedAt:      wallet.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
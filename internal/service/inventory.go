@@ -0,0 +1,209 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Redemptions and cancellation returns are recorded by the redemption flow;
+// only these reasons may be used for manual adjustments.
+var manualStockReasons = map[string]bool{"restock": true, "correction": true}
+
+func (s *productService) AdjustStock(actorID, productID string, input *types.StockAdjustmentRequest) (*types.InventoryMovementResponse, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	note := strings.TrimSpace(input.Note)
+	if input.Delta == 0 || !manualStockReasons[input.Reason] || (input.Reason == "restock" && input.Delta < 0) {
+		return nil, errors.New("invalid stock adjustment")
+	}
+	if input.Reason == "correction" && note == "" {
+		return nil, errors.New("note is required for corrections")
+	}
+
+	entry := &store.InventoryMovement{
+		ProductID:   product.ID,
+		Delta:       input.Delta,
+		Reason:      input.Reason,
+		Note:        note,
+		ActorUserID: &actorID,
+	}
+	if input.VariantID != "" {
+		_, v, err := s.findVariant(productID, input.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		entry.VariantID = &v.ID
+	} else if count, err := s.repo.CountProductVariants(product.ID); err != nil {
+		return nil, err
+	} else if count > 0 {
+		return nil, errors.New("variant is required")
+	}
+
+	if err := s.repo.ApplyStockChange(entry); err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return nil, errors.New("insufficient stock")
+		}
+		return nil, err
+	}
+	return toInventoryMovementResponse(entry), nil
+}
+
+func (s *productService) GetStockMovements(productID string, page, limit int) ([]types.InventoryMovementResponse, types.PaginationMeta, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil {
+		return nil, types.PaginationMeta{}, errors.New("product not found")
+	}
+
+	movements, total, err := s.repo.ListInventoryMovements(productID, page, limit)
+	if err != nil {
+		return nil, types.PaginationMeta{}, err
+	}
+
+	res := make([]types.InventoryMovementResponse, 0, len(movements))
+	for i := range movements {
+		res = append(res, *toInventoryMovementResponse(&movements[i]))
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+	return res, types.PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   int(total),
+		ItemsPerPage: limit,
+	}, nil
+}
+
+// stockChange turns setting stock to an absolute value into a logged delta,
+// so overwrites through product, variant or catalog updates leave an entry.
+func stockChange(actorID, productID string, variantID *string, current, target int, reason, note string) *store.InventoryMovement {
+	if target == current {
+		return nil
+	}
+	return &store.InventoryMovement{
+		ProductID:   productID,
+		VariantID:   variantID,
+		Delta:       target - current,
+		Reason:      reason,
+		Note:        note,
+		ActorUserID: actorIDPtr(actorID),
+	}
+}
+
+func actorIDPtr(actorID string) *string {
+	if actorID == "" {
+		return nil
+	}
+	return &actorID
+}
+
+func (s *adminService) GetStockForecast(windowDays, horizonDays int) ([]types.StockForecastItem, error) {
+	if windowDays <= 0 || windowDays > 365 || horizonDays <= 0 {
+		return nil, errors.New("invalid forecast window")
+	}
+
+	now := time.Now()
+	rows, err := s.repo.FetchStockDemand(now.AddDate(0, 0, -windowDays))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]types.StockForecastItem, 0, len(rows))
+	for _, row := range rows {
+		rate := float64(row.Redeemed) / float64(windowDays)
+		if rate <= 0 {
+			continue
+		}
+		days := float64(row.StockQuantity) / rate
+		if days > float64(horizonDays) {
+			continue
+		}
+		items = append(items, types.StockForecastItem{
+			ProductID:           row.ProductID,
+			Name:                row.Name,
+			StockQuantity:       row.StockQuantity,
+			LowStockThreshold:   row.LowStockThreshold,
+			RedeemedInWindow:    row.Redeemed,
+			DailyRate:           rate,
+			DaysRemaining:       days,
+			ProjectedStockoutAt: now.Add(time.Duration(days * float64(24*time.Hour))).Format(time.RFC3339),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].DaysRemaining < items[j].DaysRemaining })
+	return items, nil
+}
+
+func (s *adminService) GetWishlistedOutOfStock(limit int) ([]types.WishlistDemandItem, error) {
+	rows, err := s.repo.FetchMostWishlistedOutOfStock(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]types.WishlistDemandItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, types.WishlistDemandItem{
+			ProductID:     row.ProductID,
+			Name:          row.Name,
+			StockQuantity: row.StockQuantity,
+			Wishlisted:    row.Wishlisted,
+		})
+	}
+	return items, nil
+}
+
+func (s *adminService) GetNotifications(unreadOnly bool, page, limit int) ([]types.AdminNotificationResponse, int64, error) {
+	notifications, total, err := s.repo.ListAdminNotifications(unreadOnly, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := make([]types.AdminNotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		item := types.AdminNotificationResponse{
+			ID:        n.ID,
+			Kind:      n.Kind,
+			ProductID: n.ProductID,
+			Message:   n.Message,
+			CreatedAt: n.CreatedAt.Format(time.RFC3339),
+		}
+		if n.ReadAt != nil {
+			readAt := n.ReadAt.Format(time.RFC3339)
+			item.ReadAt = &readAt
+		}
+		res = append(res, item)
+	}
+	return res, total, nil
+}
+
+func (s *adminService) MarkNotificationRead(id string) error {
+	found, err := s.repo.MarkAdminNotificationRead(id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("not found")
+	}
+	return nil
+}
+
+func toInventoryMovementResponse(m *store.InventoryMovement) *types.InventoryMovementResponse {
+	return &types.InventoryMovementResponse{
+		ID:          m.ID,
+		ProductID:   m.ProductID,
+		VariantID:   m.VariantID,
+		Delta:       m.Delta,
+		StockAfter:  m.StockAfter,
+		Reason:      m.Reason,
+		ReferenceID: m.ReferenceID,
+		Note:        m.Note,
+		ActorUserID: m.ActorUserID,
+		CreatedAt:   m.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"Start/internal/shared/utils"
+	"Start/internal/store"
+	"Start/internal/types"
+	"encoding/json"
+	"errors"
+	"gorm.io/datatypes"
+	"strings"
+)
+
+func decodeTranslations(raw datatypes.JSON) types.Translations {
+	var t types.Translations
+	if len(raw) == 0 || json.Unmarshal(raw, &t) != nil {
+		return nil
+	}
+	return t
+}
+
+// mergeTranslations applies the input on top of the stored translations. The
+// default locale lives in the base columns, so it cannot be translated, and a
+// locale whose name and description are both empty is dropped.
+func mergeTranslations(raw datatypes.JSON, input types.Translations) (datatypes.JSON, error) {
+	merged := decodeTranslations(raw)
+	if merged == nil {
+		merged = types.Translations{}
+	}
+	for locale, t := range input {
+		locale = strings.ToLower(strings.TrimSpace(locale))
+		if !utils.SupportedLocales[locale] || locale == utils.DefaultLocale {
+			return nil, errors.New("invalid translation")
+		}
+		t.Name = strings.TrimSpace(t.Name)
+		t.Description = strings.TrimSpace(t.Description)
+		if t.Name == "" && t.Description == "" {
+			delete(merged, locale)
+			continue
+		}
+		if t.Name == "" {
+			return nil, errors.New("invalid translation")
+		}
+		merged[locale] = t
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(merged)
+}
+
+// localizedText returns the name and description in the locale, falling back
+// to the base text for the default locale or a missing translation.
+func localizedText(raw datatypes.JSON, locale, name, description string) (string, string) {
+	if locale == utils.DefaultLocale {
+		return name, description
+	}
+	t, ok := decodeTranslations(raw)[locale]
+	if !ok || t.Name == "" {
+		return name, description
+	}
+	if t.Description == "" {
+		return t.Name, description
+	}
+	return t.Name, t.Description
+}
+
+func localizeCategory(c *store.Category, locale string) {
+	c.Name, c.Description = localizedText(c.Translations, locale, c.Name, c.Description)
+}
+
+func localizeProduct(p *store.Product, locale string) {
+	p.Name, p.Description = localizedText(p.Translations, locale, p.Name, p.Description)
+	localizeCategory(&p.Category, locale)
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/shared/utils"
+	"Start/internal/store"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LoginAttempts struct {
+	Failures          int
+	LastFailureAt     time.Time
+	PreviousFailureAt time.Time
+}
+
+// LoginAttemptStore keeps login attempt counters. Attempts are recorded before
+// the credentials are checked and each call returns the updated counter, so
+// parallel guesses cannot all pass a check made before any of them counted.
+// The in-memory store is enough for a single instance; multi-instance
+// deployments need a shared store such as the database-backed one so every
+// node sees the same lockout state.
+type LoginAttemptStore interface {
+	Get(key string) (LoginAttempts, error)
+	// RecordAttempt counts an attempt and remembers when the previous one was
+	// made; the count starts over once that is older than window.
+	RecordAttempt(key string, now time.Time, window time.Duration) (LoginAttempts, error)
+	// RecordWindowAttempt counts an attempt in a fixed window that opens with
+	// the first attempt; LastFailureAt is the start of the window.
+	RecordWindowAttempt(key string, now time.Time, window time.Duration) (LoginAttempts, error)
+	// Forgive takes one attempt back off the count.
+	Forgive(key string) error
+	Reset(key string) error
+}
+
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "account temporarily locked"
+	}
+	return "too many login attempts"
+}
+
+type LoginThrottle struct {
+	store           LoginAttemptStore
+	maxFailures     int
+	maxIPFailures   int
+	lockoutDuration time.Duration
+	baseDelay       time.Duration
+}
+
+func NewLoginThrottle(store LoginAttemptStore) *LoginThrottle {
+	return &LoginThrottle{
+		store:           store,
+		maxFailures:     utils.EnvInt("LOGIN_MAX_FAILURES", 5),
+		maxIPFailures:   utils.EnvInt("LOGIN_MAX_IP_FAILURES", 50),
+		lockoutDuration: time.Duration(utils.EnvInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		baseDelay:       time.Second,
+	}
+}
+
+// Attempt records a login attempt for the account and the client IP and
+// rejects it when either is over its limit. Callers run it before checking
+// credentials and call Succeed once they pass.
+func (t *LoginThrottle) Attempt(email, ip string, now time.Time) error {
+	if ip != "" {
+		byIP, err := t.store.RecordWindowAttempt(ipKey(ip), now, t.lockoutDuration)
+		if err != nil {
+			return err
+		}
+		if byIP.Failures > t.maxIPFailures {
+			return &LoginThrottledError{RetryAfter: byIP.LastFailureAt.Add(t.lockoutDuration).Sub(now)}
+		}
+	}
+
+	// Attempts on a locked account are turned away without being counted, so
+	// the lock runs out lockoutDuration after it started however often
+	// someone keeps trying.
+	if err := t.CheckLocked(email, now); err != nil {
+		return err
+	}
+	account, err := t.store.RecordAttempt(accountKey(email), now, t.lockoutDuration)
+	if err != nil {
+		return err
+	}
+	if wait, locked := t.delay(account, now); wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait, Locked: locked}
+	}
+	return nil
+}
+
+// CheckLocked reports whether the account is locked without recording an
+// attempt, for sign-ins that do not involve guessing a secret.
+func (t *LoginThrottle) CheckLocked(email string, now time.Time) error {
+	account, err := t.store.Get(accountKey(email))
+	if err != nil {
+		return err
+	}
+	if account.Failures < t.maxFailures {
+		return nil
+	}
+	if wait := account.LastFailureAt.Add(t.lockoutDuration).Sub(now); wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait, Locked: true}
+	}
+	return nil
+}
+
+// Succeed clears the account's attempts and takes the successful one back off
+// the IP's count.
+func (t *LoginThrottle) Succeed(email, ip string) error {
+	if err := t.store.Reset(accountKey(email)); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return t.store.Forgive(ipKey(ip))
+}
+
+func (t *LoginThrottle) Unlock(email string) error {
+	return t.store.Reset(accountKey(email))
+}
+
+// delay returns how long the caller must wait before the attempt just
+// recorded in a would be allowed. Each earlier failure doubles the wait since
+// the previous attempt until maxFailures is reached, after which the account
+// is locked for the full lockout duration.
+func (t *LoginThrottle) delay(a LoginAttempts, now time.Time) (time.Duration, bool) {
+	earlier := a.Failures - 1
+	if earlier <= 0 {
+		return 0, false
+	}
+
+	if earlier >= t.maxFailures {
+		return a.LastFailureAt.Add(t.lockoutDuration).Sub(now), true
+	}
+
+	wait := t.lockoutDuration
+	if shift := earlier - 1; shift < 20 && t.baseDelay<<shift < wait {
+		wait = t.baseDelay << shift
+	}
+	return a.PreviousFailureAt.Add(wait).Sub(now), false
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+const memoryStorePruneSize = 10000
+
+type memoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]LoginAttempts
+}
+
+func NewMemoryLoginAttemptStore() LoginAttemptStore {
+	return &memoryLoginAttemptStore{attempts: make(map[string]LoginAttempts)}
+}
+
+func (m *memoryLoginAttemptStore) Get(key string) (LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.attempts[key], nil
+}
+
+func (m *memoryLoginAttemptStore) RecordAttempt(key string, now time.Time, window time.Duration) (LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(now, window)
+	a := m.attempts[key]
+	if a.LastFailureAt.Before(now.Add(-window)) {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.PreviousFailureAt = a.LastFailureAt
+	a.LastFailureAt = now
+	m.attempts[key] = a
+	return a, nil
+}
+
+func (m *memoryLoginAttemptStore) RecordWindowAttempt(key string, now time.Time, window time.Duration) (LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(now, window)
+	a := m.attempts[key]
+	if a.LastFailureAt.Before(now.Add(-window)) {
+		a.Failures = 0
+		a.LastFailureAt = now
+	}
+	a.Failures++
+	m.attempts[key] = a
+	return a, nil
+}
+
+func (m *memoryLoginAttemptStore) Forgive(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if a, ok := m.attempts[key]; ok && a.Failures > 0 {
+		a.Failures--
+		m.attempts[key] = a
+	}
+	return nil
+}
+
+func (m *memoryLoginAttemptStore) prune(now time.Time, window time.Duration) {
+	if len(m.attempts) < memoryStorePruneSize {
+		return
+	}
+	for k, v := range m.attempts {
+		if v.LastFailureAt.Before(now.Add(-window)) {
+			delete(m.attempts, k)
+		}
+	}
+}
+
+func (m *memoryLoginAttemptStore) Reset(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, key)
+	return nil
+}
+
+type databaseLoginAttemptStore struct {
+	repo *repository.Repository
+}
+
+func NewDatabaseLoginAttemptStore(repo *repository.Repository) LoginAttemptStore {
+	return &databaseLoginAttemptStore{repo: repo}
+}
+
+func (d *databaseLoginAttemptStore) Get(key string) (LoginAttempts, error) {
+	a, err := d.repo.GetLoginAttempt(key)
+	if err != nil || a == nil {
+		return LoginAttempts{}, err
+	}
+	return toLoginAttempts(a), nil
+}
+
+func (d *databaseLoginAttemptStore) RecordAttempt(key string, now time.Time, window time.Duration) (LoginAttempts, error) {
+	a, err := d.repo.RecordLoginAttempt(key, now, now.Add(-window))
+	if err != nil {
+		return LoginAttempts{}, err
+	}
+	return toLoginAttempts(a), nil
+}
+
+func (d *databaseLoginAttemptStore) RecordWindowAttempt(key string, now time.Time, window time.Duration) (LoginAttempts, error) {
+	a, err := d.repo.RecordLoginAttemptInWindow(key, now, now.Add(-window))
+	if err != nil {
+		return LoginAttempts{}, err
+	}
+	return toLoginAttempts(a), nil
+}
+
+func (d *databaseLoginAttemptStore) Forgive(key string) error {
+	return d.repo.ForgiveLoginAttempt(key)
+}
+
+func (d *databaseLoginAttemptStore) Reset(key string) error {
+	return d.repo.DeleteLoginAttempt(key)
+}
+
+func toLoginAttempts(a *store.LoginAttempt) LoginAttempts {
+	attempts := LoginAttempts{Failures: a.Failures, LastFailureAt: a.LastFailureAt}
+	if a.PreviousFailureAt != nil {
+		attempts.PreviousFailureAt = *a.PreviousFailureAt
+	}
+	return attempts
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"time"
+)
+
+func offerActive(p *store.Product, now time.Time) bool {
+	if !p.IsOffer {
+		return false
+	}
+	if p.OfferStartsAt != nil && now.Before(*p.OfferStartsAt) {
+		return false
+	}
+	if p.OfferEndsAt != nil && !now.Before(*p.OfferEndsAt) {
+		return false
+	}
+	if p.OfferQuantityCap != nil && p.OfferRedeemedQuantity >= *p.OfferQuantityCap {
+		return false
+	}
+	return true
+}
+
+// unitPoints prices one unit of the product or one of its variants. A live
+// promotional price applies to every variant; otherwise the variant's own
+// points override the product's.
+func unitPoints(p *store.Product, v *store.ProductVariant, now time.Time) int {
+	if p.OfferPoints != nil && offerActive(p, now) {
+		return *p.OfferPoints
+	}
+	if v != nil && v.RedemptionPoints != nil {
+		return *v.RedemptionPoints
+	}
+	return p.RedemptionPoints
+}
+
+// applyOfferSchedule reports whether the offer window moved, which resets the
+// redeemed count for the new run.
+func applyOfferSchedule(p *store.Product, in types.OfferSchedule) (bool, error) {
+	windowChanged := false
+	if in.OfferStartsAt != nil {
+		windowChanged = windowChanged || p.OfferStartsAt == nil || !p.OfferStartsAt.Equal(*in.OfferStartsAt)
+		p.OfferStartsAt = in.OfferStartsAt
+	}
+	if in.OfferEndsAt != nil {
+		windowChanged = windowChanged || p.OfferEndsAt == nil || !p.OfferEndsAt.Equal(*in.OfferEndsAt)
+		p.OfferEndsAt = in.OfferEndsAt
+	}
+	if in.OfferPoints != nil {
+		if *in.OfferPoints <= 0 {
+			return false, errors.New("invalid offer points")
+		}
+		p.OfferPoints = in.OfferPoints
+	}
+	if in.OfferQuantityCap != nil {
+		if *in.OfferQuantityCap <= 0 {
+			return false, errors.New("invalid offer quantity cap")
+		}
+		p.OfferQuantityCap = in.OfferQuantityCap
+	}
+	if p.OfferStartsAt != nil && p.OfferEndsAt != nil && !p.OfferEndsAt.After(*p.OfferStartsAt) {
+		return false, errors.New("invalid offer window")
+	}
+
+	// A new window starts a new offer run, so the cap counts from zero again.
+	if windowChanged {
+		p.OfferRedeemedQuantity = 0
+	}
+	return windowChanged, nil
+}
+
+func clearOfferSchedule(p *store.Product) {
+	p.OfferStartsAt = nil
+	p.OfferEndsAt = nil
+	p.OfferPoints = nil
+	p.OfferQuantityCap = nil
+	p.OfferRedeemedQuantity = 0
+}
+
+func toOfferResponse(p *store.Product, now time.Time) *types.OfferResponse {
+	if !p.IsOffer {
+		return nil
+	}
+
+	res := &types.OfferResponse{
+		Active:      offerActive(p, now),
+		Points:      p.RedemptionPoints,
+		QuantityCap: p.OfferQuantityCap,
+	}
+	if p.OfferPoints != nil {
+		res.Points = *p.OfferPoints
+	}
+	if p.OfferStartsAt != nil {
+		s := p.OfferStartsAt.Format(time.RFC3339)
+		res.StartsAt = &s
+	}
+	if p.OfferEndsAt != nil {
+		e := p.OfferEndsAt.Format(time.RFC3339)
+		res.EndsAt = &e
+	}
+	if p.OfferQuantityCap != nil {
+		remaining := *p.OfferQuantityCap - p.OfferRedeemedQuantity
+		if remaining < 0 {
+			remaining = 0
+		}
+		res.RemainingCap = &remaining
+	}
+	return res
+}
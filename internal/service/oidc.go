@@ -0,0 +1,194 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/shared/oidc"
+	"Start/internal/store"
+	"Start/internal/types"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+var usernameUnsafeChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+type oidcService struct {
+	repo      *repository.Repository
+	providers map[string]*oidc.Provider
+	throttle  *LoginThrottle
+}
+
+func NewOIDCService(repo *repository.Repository, providers map[string]*oidc.Provider, throttle *LoginThrottle) OIDCService {
+	return &oidcService{repo: repo, providers: providers, throttle: throttle}
+}
+
+func (s *oidcService) StartLogin(providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", errors.New("unknown provider")
+	}
+
+	_ = s.repo.DeleteOIDCLoginStatesBefore(time.Now().Add(-oidcStateTTL))
+
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		return "", err
+	}
+	state, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.CreateOIDCLoginState(&store.OIDCLoginState{
+		State:        state,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(state, nonce, challenge)
+}
+
+func (s *oidcService) CompleteLogin(providerName, code, state string) (*types.LoginResponse, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, errors.New("unknown provider")
+	}
+
+	saved, err := s.repo.TakeOIDCLoginState(state)
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil || saved.Provider != providerName || time.Since(saved.CreatedAt) > oidcStateTTL {
+		return nil, errors.New("invalid state")
+	}
+
+	claims, err := provider.Exchange(code, saved.CodeVerifier, saved.Nonce)
+	if err != nil {
+		return nil, errors.New("authentication failed")
+	}
+
+	user, err := s.resolveUser(providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// The provider vouches for the identity, not for the account: suspended
+	// users and accounts locked after failed password guesses stay out.
+	if user.Status == "suspended" || user.Status == "banned" {
+		return nil, errors.New("account " + user.Status)
+	}
+	if err := s.throttle.CheckLocked(user.Email, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return issueMFAChallenge(s.repo, user.ID)
+	}
+	return issueLoginResponse(user)
+}
+
+// resolveUser returns the user already linked to the external identity, links
+// an existing account with the same verified email, or creates a new one.
+func (s *oidcService) resolveUser(providerName string, claims *oidc.Claims) (*store.User, error) {
+	identity, err := s.repo.FindUserIdentity(providerName, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		return s.repo.FindByID(identity.UserID)
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, errors.New("email not verified")
+	}
+
+	identity = &store.UserIdentity{
+		ID:        uuid.NewString(),
+		Provider:  providerName,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: time.Now(),
+	}
+
+	existing, err := s.repo.FindByEmail(claims.Email)
+	if err == nil {
+		identity.UserID = existing.ID
+		if err := s.repo.CreateUserIdentity(identity); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	username, err := s.availableUsername(claims.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &store.User{
+		ID:        uuid.NewString(),
+		FirstName: claims.GivenName,
+		LastName:  claims.FamilyName,
+		Username:  username,
+		Email:     claims.Email,
+		Role:      "user",
+		Status:    "active",
+		CreatedAt: time.Now(),
+	}
+	identity.UserID = user.ID
+	if err := s.repo.CreateUserWithIdentity(user, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *oidcService) availableUsername(email string) (string, error) {
+	base := strings.ToLower(strings.SplitN(email, "@", 2)[0])
+	base = usernameUnsafeChars.ReplaceAllString(base, "")
+	if base == "" {
+		base = "user"
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		taken, err := s.repo.IsUsernameTaken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		candidate = base + "-" + hex.EncodeToString(suffix)
+	}
+	return "", errors.New("could not allocate username")
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
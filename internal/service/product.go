@@ -7,7 +7,10 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/google/uuid"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
 )
 
 type productService struct {
@@ -18,8 +21,11 @@ func NewProductService(repo *repository.Repository) ProductService {
 	return &productService{repo: repo}
 }
 
-func (s *productService) GetAllProducts(filters types.ProductFilters, page, limit int, sortBy, sortOrder string) ([]store.Product, types.PaginationMeta, error) {
-	validSort := map[string]bool{"name": true, "redemption_points": true, "stock_quantity": true}
+func (s *productService) GetAllProducts(filters types.ProductFilters, page, limit int, sortBy, sortOrder, locale string) ([]store.Product, types.PaginationMeta, error) {
+	validSort := map[string]bool{"name": true, "redemption_points": true, "stock_quantity": true, "rating_average": true}
+	if sortBy == "rating" {
+		sortBy = "rating_average"
+	}
 	if !validSort[sortBy] {
 		sortBy = "created_at"
 	}
@@ -31,6 +37,9 @@ func (s *productService) GetAllProducts(filters types.ProductFilters, page, limi
 	if err != nil {
 		return nil, types.PaginationMeta{}, err
 	}
+	for i := range prods {
+		localizeProduct(&prods[i], locale)
+	}
 
 	totalPages := (int(total) + limit - 1) / limit
 	return prods, types.PaginationMeta{
@@ -41,13 +50,27 @@ func (s *productService) GetAllProducts(filters types.ProductFilters, page, limi
 	}, nil
 }
 
-func (s *productService) SearchProducts(query string, filters types.ProductFilters, page, limit int) ([]store.Product, types.PaginationMeta, error) {
-	prods, total, err := s.repo.SearchProducts(query, filters, page, limit)
+func (s *productService) SearchProducts(query, lang, locale string, filters types.ProductFilters, page, limit int) ([]types.ProductSearchHit, types.PaginationMeta, error) {
+	rows, total, err := s.repo.SearchProducts(query, searchConfig(lang, query), locale, filters, page, limit)
 	if err != nil {
 		return nil, types.PaginationMeta{}, err
 	}
+
+	hits := make([]types.ProductSearchHit, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		category := &store.Category{ID: row.CategoryID, Name: row.CategoryName}
+		localizeProduct(&row.Product, locale)
+		hits = append(hits, types.ProductSearchHit{
+			ProductResponse:    ToProductResponse(&row.Product, category),
+			Score:              row.Rank,
+			NameHighlight:      row.NameHighlight,
+			DescriptionSnippet: row.DescriptionSnippet,
+		})
+	}
+
 	totalPages := (int(total) + limit - 1) / limit
-	return prods, types.PaginationMeta{
+	return hits, types.PaginationMeta{
 		CurrentPage:  page,
 		TotalPages:   totalPages,
 		TotalItems:   int(total),
@@ -55,41 +78,191 @@ func (s *productService) SearchProducts(query string, filters types.ProductFilte
 	}, nil
 }
 
-func (s *productService) CreateProduct(input *types.CreateProductRequest) (*types.ProductResponse, error) {
+var productPointsBuckets = []types.PointsRange{
+	{Min: 0, Max: 99},
+	{Min: 100, Max: 499},
+	{Min: 500, Max: 999},
+	{Min: 1000, Max: 4999},
+	{Min: 5000},
+}
+
+const productTagFacetLimit = 50
+
+// SearchProductFacets counts the search results per facet value. Each facet is
+// counted with every filter applied except its own, so selecting one value
+// does not hide the alternatives.
+func (s *productService) SearchProductFacets(query, lang, locale string, filters types.ProductFilters) (*types.ProductFacets, error) {
+	config := searchConfig(lang, query)
+	facets := &types.ProductFacets{}
+
+	byCategory := filters
+	byCategory.CategoryIDs = nil
+	direct, err := s.repo.CountSearchProductsByCategory(query, config, byCategory)
+	if err != nil {
+		return nil, err
+	}
+	if facets.Categories, err = s.rollUpCategoryCounts(direct, locale); err != nil {
+		return nil, err
+	}
+
+	byTag := filters
+	byTag.Tags = nil
+	if facets.Tags, err = s.repo.CountSearchProductsByTag(query, config, byTag, productTagFacetLimit); err != nil {
+		return nil, err
+	}
+
+	byPoints := filters
+	byPoints.MinPoints, byPoints.MaxPoints = 0, 0
+	counts, err := s.repo.CountSearchProductsByPoints(query, config, byPoints, productPointsBuckets)
+	if err != nil {
+		return nil, err
+	}
+	for i, pr := range productPointsBuckets {
+		facets.Points = append(facets.Points, types.PointsFacet{PointsRange: pr, Count: counts[i]})
+	}
+
+	byOffer := filters
+	byOffer.IsOffer = nil
+	offer, regular, err := s.repo.CountSearchProductsByOffer(query, config, byOffer)
+	if err != nil {
+		return nil, err
+	}
+	facets.Offer = []types.FacetCount{{Value: "offer", Count: offer}, {Value: "regular", Count: regular}}
+
+	byStock := filters
+	byStock.InStock = nil
+	inStock, outOfStock, err := s.repo.CountSearchProductsByStock(query, config, byStock)
+	if err != nil {
+		return nil, err
+	}
+	facets.Stock = []types.FacetCount{{Value: "in_stock", Count: inStock}, {Value: "out_of_stock", Count: outOfStock}}
+
+	return facets, nil
+}
+
+// rollUpCategoryCounts adds each category's direct count to all of its
+// ancestors so parent categories report the products of their subtree.
+func (s *productService) rollUpCategoryCounts(direct map[string]int64, locale string) ([]types.CategoryFacet, error) {
+	categories, err := s.repo.GetAllCategories(nil)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*store.Category, len(categories))
+	for i := range categories {
+		localizeCategory(&categories[i], locale)
+		byID[categories[i].ID] = &categories[i]
+	}
+
+	total := make(map[string]int64)
+	for id, count := range direct {
+		visited := map[string]bool{}
+		for c := byID[id]; c != nil && !visited[c.ID]; {
+			visited[c.ID] = true
+			total[c.ID] += count
+			if c.ParentCategoryID == nil {
+				break
+			}
+			c = byID[*c.ParentCategoryID]
+		}
+	}
+
+	facets := make([]types.CategoryFacet, 0, len(total))
+	for id, count := range total {
+		c := byID[id]
+		facets = append(facets, types.CategoryFacet{
+			ID:               c.ID,
+			Name:             c.Name,
+			ParentCategoryID: c.ParentCategoryID,
+			Count:            count,
+			DirectCount:      direct[id],
+		})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+	return facets, nil
+}
+
+// searchConfig picks the text search configuration from the requested
+// language, or from the script of the query when no language is given.
+func searchConfig(lang, query string) string {
+	switch strings.ToLower(lang) {
+	case "ar":
+		return "arabic"
+	case "en":
+		return "english"
+	}
+	for _, r := range query {
+		if unicode.Is(unicode.Arabic, r) {
+			return "arabic"
+		}
+	}
+	return "english"
+}
+
+func (s *productService) CreateProduct(actorID string, input *types.CreateProductRequest) (*types.ProductResponse, error) {
 	cat, err := s.repo.GetCategoryByID(input.CategoryID)
-	if err != nil || cat == nil {
+	if err != nil || cat == nil || !cat.IsActive {
 		return nil, errors.New("invalid category")
 	}
+	if input.StockQuantity < 0 {
+		return nil, errors.New("invalid stock quantity")
+	}
+	if input.LowStockThreshold != nil && *input.LowStockThreshold < 0 {
+		return nil, errors.New("invalid low stock threshold")
+	}
 
 	tagsJSON, err := json.Marshal(input.Tags)
 	if err != nil {
 		return nil, err
 	}
+	translations, err := mergeTranslations(nil, input.Translations)
+	if err != nil {
+		return nil, err
+	}
 
 	p := &store.Product{
-		ID:               uuid.NewString(),
-		Name:             input.Name,
-		Description:      input.Description,
-		CategoryID:       input.CategoryID,
-		RedemptionPoints: input.RedemptionPoints,
-		StockQuantity:    input.StockQuantity,
-		IsOffer:          input.IsOffer,
-		CreatedAt:        time.Now(),
-		Tags:             tagsJSON,
+		ID:                uuid.NewString(),
+		Name:              input.Name,
+		Description:       input.Description,
+		CategoryID:        input.CategoryID,
+		RedemptionPoints:  input.RedemptionPoints,
+		IsOffer:           input.IsOffer,
+		IsActive:          true,
+		CreatedAt:         time.Now(),
+		Tags:              tagsJSON,
+		LowStockThreshold: input.LowStockThreshold,
+		Translations:      translations,
 	}
 
 	if input.ImageURL != nil {
 		p.ImageURL = *input.ImageURL
 	}
+	if _, err := applyOfferSchedule(p, input.OfferSchedule); err != nil {
+		return nil, err
+	}
+	if err := applyCreditPaymentRule(p, input.CreditPayment); err != nil {
+		return nil, err
+	}
+	if err := applyRedemptionLimits(p, input.RedemptionLimits); err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.CreateProduct(p); err != nil {
+	opening := stockChange(actorID, p.ID, nil, 0, input.StockQuantity, "restock", "opening stock")
+	if err := s.repo.CreateProduct(p, opening); err != nil {
 		return nil, err
 	}
+	if opening != nil {
+		p.StockQuantity = opening.StockAfter
+	}
 
 	return ToProductResponse(p, cat), nil
 }
 
-func (s *productService) UpdateProduct(id string, input *types.UpdateProductRequest) (*types.ProductResponse, error) {
+func (s *productService) UpdateProduct(actorID, id string, input *types.UpdateProductRequest) (*types.ProductResponse, error) {
 	existing, err := s.repo.GetProductByID(id)
 	if err != nil || existing == nil {
 		return nil, errors.New("product not found")
@@ -103,7 +276,7 @@ func (s *productService) UpdateProduct(id string, input *types.UpdateProductRequ
 	}
 	if input.CategoryID != nil {
 		cat, _ := s.repo.GetCategoryByID(*input.CategoryID)
-		if cat == nil {
+		if cat == nil || !cat.IsActive {
 			return nil, errors.New("invalid category ID")
 		}
 		existing.CategoryID = *input.CategoryID
@@ -111,8 +284,17 @@ func (s *productService) UpdateProduct(id string, input *types.UpdateProductRequ
 	if input.RedemptionPoints != nil {
 		existing.RedemptionPoints = *input.RedemptionPoints
 	}
+	var stock *store.InventoryMovement
 	if input.StockQuantity != nil {
-		existing.StockQuantity = *input.StockQuantity
+		if *input.StockQuantity < 0 {
+			return nil, errors.New("invalid stock quantity")
+		}
+		if count, err := s.repo.CountProductVariants(existing.ID); err != nil {
+			return nil, err
+		} else if count > 0 {
+			return nil, errors.New("stock is managed by variants")
+		}
+		stock = stockChange(actorID, existing.ID, nil, existing.StockQuantity, *input.StockQuantity, "correction", "set through product update")
 	}
 	if input.IsOffer != nil {
 		existing.IsOffer = *input.IsOffer
@@ -127,16 +309,83 @@ func (s *productService) UpdateProduct(id string, input *types.UpdateProductRequ
 		}
 		existing.Tags = tagsJSON
 	}
+	if input.ClearOfferSchedule {
+		clearOfferSchedule(existing)
+	}
+	windowChanged, err := applyOfferSchedule(existing, input.OfferSchedule)
+	if err != nil {
+		return nil, err
+	}
+	resetOfferCount := input.ClearOfferSchedule || windowChanged
+	if input.ClearLowStockThreshold {
+		existing.LowStockThreshold = nil
+	}
+	if input.LowStockThreshold != nil {
+		if *input.LowStockThreshold < 0 {
+			return nil, errors.New("invalid low stock threshold")
+		}
+		existing.LowStockThreshold = input.LowStockThreshold
+	}
+	if input.Translations != nil {
+		if existing.Translations, err = mergeTranslations(existing.Translations, input.Translations); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyCreditPaymentRule(existing, input.CreditPayment); err != nil {
+		return nil, err
+	}
+	if err := applyRedemptionLimits(existing, input.RedemptionLimits); err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.UpdateProduct(existing); err != nil {
+	if err := s.repo.UpdateProduct(existing, stock, resetOfferCount); err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return nil, errors.New("insufficient stock")
+		}
 		return nil, err
 	}
+	if stock != nil {
+		existing.StockQuantity = stock.StockAfter
+	}
 
 	category, _ := s.repo.GetCategoryByID(existing.CategoryID)
 
 	return ToProductResponse(existing, category), nil
 }
 
+func (s *productService) GetProductByID(id, locale string) (*types.ProductResponse, error) {
+	p, err := s.repo.GetActiveProductWithCategory(id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, errors.New("product not found")
+	}
+	localizeProduct(p, locale)
+	return ToProductResponse(p, &p.Category), nil
+}
+
 func (s *productService) DeleteProduct(id string) error {
-	return s.repo.DeleteProduct(id)
+	p, err := s.repo.GetProductByID(id)
+	if err != nil || p == nil {
+		return errors.New("product not found")
+	}
+	return s.repo.SetProductActive(id, false)
+}
+
+func (s *productService) RestoreProduct(id string) (*types.ProductResponse, error) {
+	p, err := s.repo.GetProductByID(id)
+	if err != nil || p == nil {
+		return nil, errors.New("product not found")
+	}
+	if err := s.repo.SetProductActive(id, true); err != nil {
+		return nil, err
+	}
+	p.IsActive = true
+
+	category, _ := s.repo.GetCategoryByID(p.CategoryID)
+	if category == nil {
+		category = &store.Category{}
+	}
+	return ToProductResponse(p, category), nil
 }
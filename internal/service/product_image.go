@@ -0,0 +1,227 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/shared/storage"
+	"Start/internal/shared/utils"
+	"Start/internal/store"
+	"Start/internal/types"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"time"
+)
+
+const productThumbnailSize = 320
+
+var productImageMaxCount = utils.EnvInt("PRODUCT_IMAGE_MAX_COUNT", 10)
+
+type productImageService struct {
+	repo  *repository.Repository
+	blobs storage.BlobStore
+}
+
+func NewProductImageService(repo *repository.Repository, blobs storage.BlobStore) ProductImageService {
+	return &productImageService{repo: repo, blobs: blobs}
+}
+
+// UploadImages stores each upload with a thumbnail. If any file is rejected,
+// the images already stored by the request are removed again. A product's
+// first image becomes its primary image, as does the first new one when
+// makePrimary is set.
+func (s *productImageService) UploadImages(productID string, uploads []types.ImageUpload, makePrimary bool) ([]types.ProductImageResponse, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil {
+		return nil, errors.New("product not found")
+	}
+	if len(uploads) == 0 {
+		return nil, errors.New("no images uploaded")
+	}
+
+	existing, err := s.repo.ListProductImages(productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing)+len(uploads) > productImageMaxCount {
+		return nil, errors.New("too many images")
+	}
+
+	ctx := context.Background()
+	nextPosition := 0
+	hasPrimary := false
+	for _, img := range existing {
+		if img.Position >= nextPosition {
+			nextPosition = img.Position + 1
+		}
+		hasPrimary = hasPrimary || img.IsPrimary
+	}
+
+	var created []store.ProductImage
+	cleanup := func() {
+		for _, img := range created {
+			s.deleteBlobs(ctx, &img)
+			_ = s.repo.DeleteProductImage(img.ID)
+		}
+	}
+
+	for i, upload := range uploads {
+		if int64(len(upload.Data)) > utils.MaxImageUploadBytes {
+			cleanup()
+			return nil, errors.New("image too large")
+		}
+		decoded, contentType, err := utils.DecodeImage(upload.Data)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		thumb, thumbType, err := utils.Thumbnail(decoded, contentType, productThumbnailSize)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+
+		id := uuid.NewString()
+		img := store.ProductImage{
+			ID:           id,
+			ProductID:    productID,
+			Key:          "products/" + productID + "/" + id + utils.ImageExtensions[contentType],
+			ThumbnailKey: "products/" + productID + "/" + id + "_thumb" + utils.ImageExtensions[thumbType],
+			ContentType:  contentType,
+			SizeBytes:    int64(len(upload.Data)),
+			Width:        decoded.Bounds().Dx(),
+			Height:       decoded.Bounds().Dy(),
+			Position:     nextPosition + i,
+			CreatedAt:    time.Now(),
+		}
+
+		if err := s.blobs.Put(ctx, img.Key, bytes.NewReader(upload.Data), img.SizeBytes, contentType); err != nil {
+			cleanup()
+			return nil, err
+		}
+		if err := s.blobs.Put(ctx, img.ThumbnailKey, bytes.NewReader(thumb), int64(len(thumb)), thumbType); err != nil {
+			s.deleteBlobs(ctx, &img)
+			cleanup()
+			return nil, err
+		}
+		if err := s.repo.CreateProductImage(&img); err != nil {
+			s.deleteBlobs(ctx, &img)
+			cleanup()
+			return nil, err
+		}
+		created = append(created, img)
+	}
+
+	if makePrimary || !hasPrimary {
+		if err := s.repo.SetPrimaryProductImage(productID, created[0].ID, s.blobs.URL(created[0].Key)); err != nil {
+			return nil, err
+		}
+	}
+	return s.ListImages(productID)
+}
+
+func (s *productImageService) ListImages(productID string) ([]types.ProductImageResponse, error) {
+	images, err := s.repo.ListProductImages(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]types.ProductImageResponse, 0, len(images))
+	for i := range images {
+		res = append(res, s.toResponse(&images[i]))
+	}
+	return res, nil
+}
+
+func (s *productImageService) ReorderImages(productID string, imageIDs []string) ([]types.ProductImageResponse, error) {
+	images, err := s.repo.ListProductImages(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new order must name every image of the product exactly once.
+	remaining := make(map[string]bool, len(images))
+	for _, img := range images {
+		remaining[img.ID] = true
+	}
+	if len(imageIDs) != len(images) {
+		return nil, errors.New("invalid image order")
+	}
+	for _, id := range imageIDs {
+		if !remaining[id] {
+			return nil, errors.New("invalid image order")
+		}
+		delete(remaining, id)
+	}
+
+	if err := s.repo.ReorderProductImages(productID, imageIDs); err != nil {
+		return nil, err
+	}
+	return s.ListImages(productID)
+}
+
+func (s *productImageService) SetPrimaryImage(productID, imageID string) ([]types.ProductImageResponse, error) {
+	img, err := s.findImage(productID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetPrimaryProductImage(productID, img.ID, s.blobs.URL(img.Key)); err != nil {
+		return nil, err
+	}
+	return s.ListImages(productID)
+}
+
+func (s *productImageService) DeleteImage(productID, imageID string) error {
+	img, err := s.findImage(productID, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteProductImage(img.ID); err != nil {
+		return err
+	}
+	s.deleteBlobs(context.Background(), img)
+
+	if !img.IsPrimary {
+		return nil
+	}
+	rest, err := s.repo.ListProductImages(productID)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return s.repo.SetPrimaryProductImage(productID, "", "")
+	}
+	return s.repo.SetPrimaryProductImage(productID, rest[0].ID, s.blobs.URL(rest[0].Key))
+}
+
+func (s *productImageService) findImage(productID, imageID string) (*store.ProductImage, error) {
+	img, err := s.repo.GetProductImageByID(imageID)
+	if err != nil || img == nil || img.ProductID != productID {
+		return nil, errors.New("image not found")
+	}
+	return img, nil
+}
+
+func (s *productImageService) deleteBlobs(ctx context.Context, img *store.ProductImage) {
+	for _, key := range []string{img.Key, img.ThumbnailKey} {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+func (s *productImageService) toResponse(img *store.ProductImage) types.ProductImageResponse {
+	return types.ProductImageResponse{
+		ID:           img.ID,
+		URL:          s.blobs.URL(img.Key),
+		ThumbnailURL: s.blobs.URL(img.ThumbnailKey),
+		ContentType:  img.ContentType,
+		SizeBytes:    img.SizeBytes,
+		Width:        img.Width,
+		Height:       img.Height,
+		Position:     img.Position,
+		IsPrimary:    img.IsPrimary,
+		CreatedAt:    img.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+func (s *productService) CreateVariant(actorID, productID string, input *types.CreateVariantRequest) (*types.VariantResponse, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	sku := strings.TrimSpace(input.SKU)
+	if sku == "" || len(input.Options) == 0 {
+		return nil, errors.New("sku and options are required")
+	}
+	if input.StockQuantity < 0 || (input.RedemptionPoints != nil && *input.RedemptionPoints <= 0) {
+		return nil, errors.New("invalid variant")
+	}
+	if existing, _ := s.repo.FindProductVariantBySKU(sku); existing != nil {
+		return nil, errors.New("sku already exists")
+	}
+
+	options, err := json.Marshal(input.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &store.ProductVariant{
+		ID:               uuid.NewString(),
+		ProductID:        product.ID,
+		SKU:              sku,
+		Options:          options,
+		RedemptionPoints: input.RedemptionPoints,
+		IsActive:         true,
+		CreatedAt:        time.Now(),
+	}
+	opening := stockChange(actorID, product.ID, &v.ID, 0, input.StockQuantity, "restock", "opening stock")
+	if err := s.repo.CreateProductVariant(v, opening); err != nil {
+		return nil, err
+	}
+	if opening != nil {
+		v.StockQuantity = opening.StockAfter
+	}
+	return &toVariantResponses(product, []store.ProductVariant{*v})[0], nil
+}
+
+func (s *productService) UpdateVariant(actorID, productID, variantID string, input *types.UpdateVariantRequest) (*types.VariantResponse, error) {
+	product, v, err := s.findVariant(productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.SKU != nil {
+		sku := strings.TrimSpace(*input.SKU)
+		if sku == "" {
+			return nil, errors.New("sku and options are required")
+		}
+		if existing, _ := s.repo.FindProductVariantBySKU(sku); existing != nil && existing.ID != v.ID {
+			return nil, errors.New("sku already exists")
+		}
+		v.SKU = sku
+	}
+	if input.Options != nil {
+		if len(input.Options) == 0 {
+			return nil, errors.New("sku and options are required")
+		}
+		options, err := json.Marshal(input.Options)
+		if err != nil {
+			return nil, err
+		}
+		v.Options = options
+	}
+	var stock *store.InventoryMovement
+	if input.StockQuantity != nil {
+		if *input.StockQuantity < 0 {
+			return nil, errors.New("invalid variant")
+		}
+		stock = stockChange(actorID, product.ID, &v.ID, v.StockQuantity, *input.StockQuantity, "correction", "set through variant update")
+	}
+	if input.RedemptionPoints != nil {
+		if *input.RedemptionPoints <= 0 {
+			return nil, errors.New("invalid variant")
+		}
+		v.RedemptionPoints = input.RedemptionPoints
+	}
+
+	if err := s.repo.UpdateProductVariant(v, stock); err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return nil, errors.New("insufficient stock")
+		}
+		return nil, err
+	}
+	if stock != nil {
+		v.StockQuantity = stock.StockAfter
+	}
+	return &toVariantResponses(product, []store.ProductVariant{*v})[0], nil
+}
+
+func (s *productService) DeleteVariant(productID, variantID string) error {
+	_, v, err := s.findVariant(productID, variantID)
+	if err != nil {
+		return err
+	}
+	v.IsActive = false
+	return s.repo.UpdateProductVariant(v, nil)
+}
+
+func (s *productService) findVariant(productID, variantID string) (*store.Product, *store.ProductVariant, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil {
+		return nil, nil, errors.New("product not found")
+	}
+	v, err := s.repo.GetProductVariantByID(variantID)
+	if err != nil || v == nil || v.ProductID != product.ID || !v.IsActive {
+		return nil, nil, errors.New("variant not found")
+	}
+	return product, v, nil
+}
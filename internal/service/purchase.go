@@ -2,45 +2,75 @@ package service
 
 import (
 	"Start/internal/repository"
+	"Start/internal/shared/payment"
 	"Start/internal/store"
 	"Start/internal/types"
+	"context"
 	"errors"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"time"
 )
 
 type purchaseResponse struct {
-	repo *repository.Repository
+	repo     *repository.Repository
+	payments payment.Provider
 }
 
-func NewPurchaseService(repo *repository.Repository) PurchaseService {
-	return &purchaseResponse{repo: repo}
+func NewPurchaseService(repo *repository.Repository, payments payment.Provider) PurchaseService {
+	return &purchaseResponse{repo: repo, payments: payments}
 }
 
 func (s *purchaseResponse) CreatePurchase(userID string, input types.CreatePurchaseRequest) (*types.PurchaseResponse, error) {
-	pkg, err := s.repo.GetCreditPackageByID(input.CreditPackageID)
-	if err != nil {
+	if err := s.repo.ApplyDuePriceChanges(); err != nil {
+		return nil, err
+	}
+	pkg, price, err := s.repo.GetCreditPackageWithPrice(input.CreditPackageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, errors.New("package not found")
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	if input.PaymentMethod != "credit_card" {
+	purchaseID := uuid.NewString()
+	txnID, err := s.payments.Charge(context.Background(), payment.ChargeRequest{
+		Reference: purchaseID,
+		UserID:    userID,
+		AmountEGP: pkg.PriceEGP,
+		Method:    input.PaymentMethod,
+		Details:   input.PaymentDetails,
+	})
+	if err != nil {
 		return nil, errors.New("payment failed")
 	}
 
 	p := &store.Purchase{
-		ID:              uuid.NewString(),
-		UserID:          userID,
-		Status:          "completed",
-		Credits:         pkg.Credits,
-		CreditPackageID: input.CreditPackageID,
-		CreatedAt:       time.Now(),
+		ID:                   purchaseID,
+		UserID:               userID,
+		Status:               "completed",
+		Credits:              pkg.Credits,
+		CreditPackageID:      &pkg.ID,
+		CreatedAt:            time.Now(),
+		PriceEGP:             pkg.PriceEGP,
+		RewardPoints:         pkg.RewardPoints,
+		PaymentTransactionID: txnID,
+	}
+	if price != nil {
+		p.CreditPackagePriceID = &price.ID
 	}
 
 	if err := s.repo.CreatePurchase(p); err != nil {
 		return nil, err
 	}
 
-	_ = s.repo.AddToWallet(userID, pkg.Credits, pkg.RewardPoints)
+	_ = s.repo.ApplyWalletChange(&store.WalletTransaction{
+		UserID:       userID,
+		CreditsDelta: pkg.Credits,
+		PointsDelta:  pkg.RewardPoints,
+		Reason:       "purchase",
+		ReferenceID:  p.ID,
+	})
 
 	return ToPurchaseResponse(p, pkg), nil
 }
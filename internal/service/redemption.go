@@ -19,21 +19,17 @@ func NewRedemptionService(repo *repository.Repository) RedemptionService {
 }
 
 func (s *redemptionService) CreateRedemption(userID string, input types.CreateRedemptionRequest) (*types.RedemptionResponse, error) {
-	product, err := s.repo.GetProductByID(input.ProductID)
-	if err != nil || product == nil {
-		return nil, errors.New("product not found")
-	}
-	if !product.IsOffer {
-		return nil, errors.New("product is not available for redemption")
-	}
-	if input.Quantity <= 0 {
-		return nil, errors.New("invalid quantity")
-	}
-	if input.Quantity > product.StockQuantity {
-		return nil, errors.New("insufficient stock")
+	now := time.Now()
+	product, variant, err := s.resolveLine(userID, input.ProductID, input.VariantID, input.Quantity, now)
+	if err != nil {
+		return nil, err
 	}
 
-	pointsRequired := input.Quantity * product.RedemptionPoints
+	pointsPrice := input.Quantity * unitPoints(product, variant, now)
+	pointsRequired, rate, err := s.splitPayment(product, pointsPrice, input.CreditsUsed)
+	if err != nil {
+		return nil, err
+	}
 	wallet, err := s.repo.GetWalletByUserID(userID)
 	if err != nil || wallet == nil {
 		return nil, errors.New("user wallet not found")
@@ -41,40 +37,153 @@ func (s *redemptionService) CreateRedemption(userID string, input types.CreateRe
 	if wallet.PointsBalance < pointsRequired {
 		return nil, errors.New("insufficient points")
 	}
+	if wallet.CreditsBalance < input.CreditsUsed {
+		return nil, errors.New("insufficient credits")
+	}
 
-	redemptionID := uuid.NewString()
-	now := time.Now()
+	r := &store.Redemption{
+		ID:         uuid.NewString(),
+		UserID:     userID,
+		ProductID:  product.ID,
+		VariantID:  variantIDPtr(variant),
+		Status:     "pending",
+		Quantity:   input.Quantity,
+		PointsUsed: pointsRequired,
+		CreatedAt:  now,
 
+		CreditsUsed:     input.CreditsUsed,
+		PointsPerCredit: rate,
+	}
 	if err := s.repo.WithTx(func(tx *gorm.DB) error {
-		if err := s.repo.DeductPointsTx(tx, userID, pointsRequired); err != nil {
-			return err
-		}
-		if err := s.repo.DecrementStockTx(tx, product.ID, input.Quantity); err != nil {
+		if err := s.repo.ApplyWalletChangeTx(tx, &store.WalletTransaction{
+			UserID:       userID,
+			PointsDelta:  -pointsRequired,
+			CreditsDelta: -input.CreditsUsed,
+			Reason:       "redemption",
+			ReferenceID:  r.ID,
+		}); err != nil {
 			return err
 		}
-		r := &store.Redemption{
-			ID:        redemptionID,
-			UserID:    userID,
-			ProductID: product.ID,
-			Quantity:  input.Quantity,
-			CreatedAt: now,
-		}
-		return tx.Create(r).Error
+		return s.redeemTx(tx, product, r)
 	}); err != nil {
-		return nil, err
+		if errors.Is(err, repository.ErrInsufficientBalance) && input.CreditsUsed > 0 {
+			return nil, errors.New("insufficient balance")
+		}
+		return nil, redemptionTxError(err)
 	}
 
-	return &types.RedemptionResponse{
-		ID: redemptionID,
+	product.OfferRedeemedQuantity += r.Quantity
+	res := &types.RedemptionResponse{
+		ID: r.ID,
 		Product: types.RedemptionProduct{
 			ID:           product.ID,
 			Name:         product.Name,
 			RewardPoints: product.RedemptionPoints,
 		},
-		Quantity:   input.Quantity,
-		PointsUsed: pointsRequired,
+		VariantID:  r.VariantID,
+		Quantity:   r.Quantity,
+		PointsUsed: r.PointsUsed,
 		CreatedAt:  now.Format(time.RFC3339),
-	}, nil
+
+		CreditsUsed:     r.CreditsUsed,
+		PointsPerCredit: r.PointsPerCredit,
+	}
+	if usage, err := s.repo.RedemptionUsage(userID, product.ID, time.Now()); err == nil {
+		res.Allowance = redemptionAllowance(product, usage, time.Now())
+	}
+	return res, nil
+}
+
+// resolveLine checks that the user can redeem quantity units of the product,
+// or of the variant when it has variants, right now.
+func (s *redemptionService) resolveLine(userID, productID, variantID string, quantity int, now time.Time) (*store.Product, *store.ProductVariant, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil {
+		return nil, nil, errors.New("product not found")
+	}
+	if !product.IsActive || !offerActive(product, now) {
+		return nil, nil, errors.New("product is not available for redemption")
+	}
+	if quantity <= 0 {
+		return nil, nil, errors.New("invalid quantity")
+	}
+
+	var variant *store.ProductVariant
+	if variantID != "" {
+		variant, err = s.repo.GetProductVariantByID(variantID)
+		if err != nil || variant == nil || variant.ProductID != product.ID || !variant.IsActive {
+			return nil, nil, errors.New("variant not found")
+		}
+	} else if count, err := s.repo.CountProductVariants(product.ID); err != nil {
+		return nil, nil, err
+	} else if count > 0 {
+		return nil, nil, errors.New("variant is required")
+	}
+
+	stock := product.StockQuantity
+	if variant != nil {
+		stock = variant.StockQuantity
+	}
+	if quantity > stock {
+		return nil, nil, errors.New("insufficient stock")
+	}
+	if offerCap := product.OfferQuantityCap; offerCap != nil && product.OfferRedeemedQuantity+quantity > *offerCap {
+		return nil, nil, errors.New("offer quantity cap reached")
+	}
+	if hasRedemptionLimits(product) {
+		usage, err := s.repo.RedemptionUsage(userID, product.ID, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := checkRedemptionLimits(product, usage, quantity, now); err != nil {
+			return nil, nil, err
+		}
+	}
+	return product, variant, nil
+}
+
+// redeemTx enforces the product's per-user limits, reserves the offer
+// quantity and stock for the redemption and records it. The caller takes the
+// payment in the same transaction.
+func (s *redemptionService) redeemTx(tx *gorm.DB, product *store.Product, r *store.Redemption) error {
+	if hasRedemptionLimits(product) {
+		if err := s.repo.LockRedemptionLimitTx(tx, r.UserID, r.ProductID); err != nil {
+			return err
+		}
+		usage, err := s.repo.RedemptionUsageTx(tx, r.UserID, r.ProductID, r.CreatedAt, r.OrderID)
+		if err != nil {
+			return err
+		}
+		if err := checkRedemptionLimits(product, usage, r.Quantity, r.CreatedAt); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.ReserveOfferQuantityTx(tx, r.ProductID, r.Quantity); err != nil {
+		return err
+	}
+	if err := s.repo.ApplyStockChangeTx(tx, &store.InventoryMovement{
+		ProductID:   r.ProductID,
+		VariantID:   r.VariantID,
+		Delta:       -r.Quantity,
+		Reason:      "redemption",
+		ReferenceID: r.ID,
+		ActorUserID: &r.UserID,
+	}); err != nil {
+		return err
+	}
+	return tx.Create(r).Error
+}
+
+func redemptionTxError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrInsufficientBalance):
+		return errors.New("insufficient points")
+	case errors.Is(err, repository.ErrInsufficientStock):
+		return errors.New("insufficient stock")
+	case errors.Is(err, repository.ErrOfferUnavailable):
+		return errors.New("offer quantity cap reached")
+	}
+	return err
 }
 
 func (s *redemptionService) GetUserRedemptions(userID string, page, limit int) ([]*types.RedemptionResponse, int64, error) {
@@ -85,17 +194,7 @@ func (s *redemptionService) GetUserRedemptions(userID string, page, limit int) (
 
 	var responses []*types.RedemptionResponse
 	for _, r := range records {
-		responses = append(responses, &types.RedemptionResponse{
-			ID: r.ID,
-			Product: types.RedemptionProduct{
-				ID:           r.Product.ID,
-				Name:         r.Product.Name,
-				RewardPoints: r.Product.RedemptionPoints,
-			},
-			Quantity:   r.Quantity,
-			PointsUsed: r.Quantity * r.Product.RedemptionPoints,
-			CreatedAt:  r.CreatedAt.Format(time.RFC3339),
-		})
+		responses = append(responses, ToRedemptionResponse(r))
 	}
 	return responses, total, nil
 }
@@ -109,15 +208,12 @@ func (s *redemptionService) GetRedemptionByID(userID, id string) (*types.Redempt
 		return nil, errors.New("unauthorized")
 	}
 
-	return &types.RedemptionResponse{
-		ID: r.ID,
-		Product: types.RedemptionProduct{
-			ID:           r.Product.ID,
-			Name:         r.Product.Name,
-			RewardPoints: r.Product.RedemptionPoints,
-		},
-		Quantity:   r.Quantity,
-		PointsUsed: r.Quantity * r.Product.RedemptionPoints,
-		CreatedAt:  r.CreatedAt.Format(time.RFC3339),
-	}, nil
+	return ToRedemptionResponse(r), nil
+}
+
+func variantIDPtr(v *store.ProductVariant) *string {
+	if v == nil {
+		return nil
+	}
+	return &v.ID
 }
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"time"
+)
+
+func applyRedemptionLimits(p *store.Product, in *types.RedemptionLimits) error {
+	if in == nil {
+		return nil
+	}
+	for _, limit := range []*int{in.MaxPerUserDaily, in.MaxPerUserWeekly, in.MaxPerUserLifetime, in.CooldownMinutes} {
+		if limit != nil && *limit <= 0 {
+			return errors.New("invalid redemption limits")
+		}
+	}
+	p.MaxPerUserDaily = in.MaxPerUserDaily
+	p.MaxPerUserWeekly = in.MaxPerUserWeekly
+	p.MaxPerUserLifetime = in.MaxPerUserLifetime
+	p.CooldownMinutes = in.CooldownMinutes
+	return nil
+}
+
+func hasRedemptionLimits(p *store.Product) bool {
+	return p.MaxPerUserDaily != nil || p.MaxPerUserWeekly != nil || p.MaxPerUserLifetime != nil || p.CooldownMinutes != nil
+}
+
+func toRedemptionLimits(p *store.Product) *types.RedemptionLimits {
+	if !hasRedemptionLimits(p) {
+		return nil
+	}
+	return &types.RedemptionLimits{
+		MaxPerUserDaily:    p.MaxPerUserDaily,
+		MaxPerUserWeekly:   p.MaxPerUserWeekly,
+		MaxPerUserLifetime: p.MaxPerUserLifetime,
+		CooldownMinutes:    p.CooldownMinutes,
+	}
+}
+
+func checkRedemptionLimits(p *store.Product, usage *repository.RedemptionUsage, quantity int, now time.Time) error {
+	if exceeds(p.MaxPerUserDaily, usage.LastDay+quantity) ||
+		exceeds(p.MaxPerUserWeekly, usage.LastWeek+quantity) ||
+		exceeds(p.MaxPerUserLifetime, usage.Lifetime+quantity) {
+		return errors.New("redemption limit reached")
+	}
+	if next := nextRedemptionAt(p, usage); next != nil && now.Before(*next) {
+		return errors.New("redemption cooldown active")
+	}
+	return nil
+}
+
+func exceeds(limit *int, total int) bool {
+	return limit != nil && total > *limit
+}
+
+func nextRedemptionAt(p *store.Product, usage *repository.RedemptionUsage) *time.Time {
+	if p.CooldownMinutes == nil || usage.LastRedeemedAt == nil {
+		return nil
+	}
+	next := usage.LastRedeemedAt.Add(time.Duration(*p.CooldownMinutes) * time.Minute)
+	return &next
+}
+
+func redemptionAllowance(p *store.Product, usage *repository.RedemptionUsage, now time.Time) *types.RedemptionAllowance {
+	remaining := func(limit *int, used int) *int {
+		if limit == nil {
+			return nil
+		}
+		left := max(*limit-used, 0)
+		return &left
+	}
+
+	a := &types.RedemptionAllowance{
+		DailyRemaining:    remaining(p.MaxPerUserDaily, usage.LastDay),
+		WeeklyRemaining:   remaining(p.MaxPerUserWeekly, usage.LastWeek),
+		LifetimeRemaining: remaining(p.MaxPerUserLifetime, usage.Lifetime),
+		OfferRemaining:    remaining(p.OfferQuantityCap, p.OfferRedeemedQuantity),
+	}
+	if next := nextRedemptionAt(p, usage); next != nil && now.Before(*next) {
+		formatted := next.Format(time.RFC3339)
+		a.NextRedemptionAt = &formatted
+	}
+	return a
+}
+
+// GetAllowance reports how much of the product the user may still redeem.
+func (s *redemptionService) GetAllowance(userID, productID string) (*types.RedemptionAllowance, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil {
+		return nil, errors.New("product not found")
+	}
+	now := time.Now()
+	usage, err := s.repo.RedemptionUsage(userID, productID, now)
+	if err != nil {
+		return nil, err
+	}
+	return redemptionAllowance(product, usage, now), nil
+}
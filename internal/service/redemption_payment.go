@@ -0,0 +1,98 @@
+package service
+
+import (
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const pointsPerCreditSetting = "points_per_credit"
+
+func applyCreditPaymentRule(p *store.Product, rule *types.CreditPaymentRule) error {
+	if rule == nil {
+		return nil
+	}
+	if !rule.Allowed {
+		p.AllowCreditPayment = false
+		p.MaxCreditShare = 0
+		return nil
+	}
+	if rule.MaxSharePercent <= 0 || rule.MaxSharePercent > 100 {
+		return errors.New("invalid credit payment rule")
+	}
+	p.AllowCreditPayment = true
+	p.MaxCreditShare = rule.MaxSharePercent
+	return nil
+}
+
+// pointsPerCredit returns the conversion rate for paying redemptions with
+// credits, or zero while admins have not set one.
+func (s *redemptionService) pointsPerCredit() (int, error) {
+	setting, err := s.repo.GetSetting(pointsPerCreditSetting)
+	if err != nil || setting == nil {
+		return 0, err
+	}
+	rate, err := strconv.Atoi(setting.Value)
+	if err != nil || rate < 0 {
+		return 0, nil
+	}
+	return rate, nil
+}
+
+func (s *redemptionService) GetPaymentSettings() (*types.RedemptionPaymentSettings, error) {
+	rate, err := s.pointsPerCredit()
+	if err != nil {
+		return nil, err
+	}
+	return &types.RedemptionPaymentSettings{PointsPerCredit: rate}, nil
+}
+
+// UpdatePaymentSettings sets the conversion rate. Zero turns off paying with
+// credits; redemptions already made keep the rate they were paid at.
+func (s *redemptionService) UpdatePaymentSettings(adminID string, input types.RedemptionPaymentSettings) (*types.RedemptionPaymentSettings, error) {
+	if input.PointsPerCredit < 0 {
+		return nil, errors.New("invalid conversion rate")
+	}
+	if err := s.repo.SaveSetting(&store.Setting{
+		Key:       pointsPerCreditSetting,
+		Value:     strconv.Itoa(input.PointsPerCredit),
+		UpdatedBy: &adminID,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// splitPayment checks the credits the user wants to pay towards a points
+// price against the product's rule and returns the points still owed and the
+// rate used.
+func (s *redemptionService) splitPayment(product *store.Product, pointsPrice, creditsUsed int) (int, int, error) {
+	if creditsUsed < 0 {
+		return 0, 0, errors.New("invalid payment split")
+	}
+	if creditsUsed == 0 {
+		return pointsPrice, 0, nil
+	}
+	if !product.AllowCreditPayment {
+		return 0, 0, errors.New("credit payment not allowed")
+	}
+	rate, err := s.pointsPerCredit()
+	if err != nil {
+		return 0, 0, err
+	}
+	if rate == 0 {
+		return 0, 0, errors.New("credit payment not allowed")
+	}
+
+	covered := creditsUsed * rate
+	if covered > pointsPrice {
+		return 0, 0, errors.New("invalid payment split")
+	}
+	if covered*100 > pointsPrice*product.MaxCreditShare {
+		return 0, 0, errors.New("credit share exceeds limit")
+	}
+	return pointsPrice - covered, rate, nil
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/shared/utils"
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"gorm.io/gorm"
+	"slices"
+	"time"
+)
+
+// redemptionSLA is how long a redemption may wait before staff ship or
+// reject it.
+var redemptionSLA = time.Duration(utils.EnvInt("REDEMPTION_SLA_HOURS", 48)) * time.Hour
+
+// openRedemptionStatuses are the statuses still waiting on fulfilment staff.
+var openRedemptionStatuses = []string{"pending", "approved"}
+
+// closedRedemptionStatuses are final; nothing changes a redemption in them.
+var closedRedemptionStatuses = []string{"delivered", "rejected", "cancelled"}
+
+// bulkItemErrors are reported per item as they are; anything else is a
+// generic failure.
+var bulkItemErrors = map[string]bool{
+	"not found":                true,
+	"redemption is closed":     true,
+	"claimed by another admin": true,
+	"invalid transition":       true,
+	"reason required":          true,
+}
+
+type redemptionTransition struct {
+	from []string
+	to   string
+}
+
+var redemptionTransitions = map[string]redemptionTransition{
+	"approve": {from: []string{"pending"}, to: "approved"},
+	"ship":    {from: []string{"approved"}, to: "shipped"},
+	"deliver": {from: []string{"shipped"}, to: "delivered"},
+	"reject":  {from: []string{"pending", "approved"}, to: "rejected"},
+	"cancel":  {from: []string{"pending", "approved"}, to: "cancelled"},
+}
+
+// redemptionStatusTransitions are the moves the single-item status endpoint
+// accepts. It predates the queue and could reset a redemption to pending or
+// mark it delivered straight away, so it still can while the redemption is
+// open.
+var redemptionStatusTransitions = map[string]redemptionTransition{
+	"pending":   {from: []string{"pending", "approved"}, to: "pending"},
+	"approved":  redemptionTransitions["approve"],
+	"shipped":   redemptionTransitions["ship"],
+	"delivered": {from: []string{"pending", "approved", "shipped"}, to: "delivered"},
+	"rejected":  redemptionTransitions["reject"],
+	"cancelled": redemptionTransitions["cancel"],
+}
+
+func redemptionStatus(r *store.Redemption) string {
+	if r.Status == "" {
+		return "pending"
+	}
+	return r.Status
+}
+
+func (s *adminService) GetRedemptionQueue(adminID string, filters types.RedemptionQueueFilters, page, limit int) ([]types.RedemptionQueueItem, types.PaginationMeta, error) {
+	now := time.Now()
+	q := repository.RedemptionQueueQuery{
+		Statuses:   openRedemptionStatuses,
+		ProductID:  filters.ProductID,
+		CategoryID: filters.CategoryID,
+	}
+	if filters.Status != "" && filters.Status != "open" {
+		q.Statuses = []string{filters.Status}
+	}
+	switch filters.AssignedTo {
+	case "":
+	case "me":
+		q.AssignedTo = adminID
+	case "unassigned":
+		q.Unassigned = true
+	default:
+		q.AssignedTo = filters.AssignedTo
+	}
+
+	minAge := time.Duration(filters.MinAgeHours) * time.Hour
+	if filters.Overdue && minAge < redemptionSLA {
+		minAge = redemptionSLA
+	}
+	if minAge > 0 {
+		before := now.Add(-minAge)
+		q.CreatedBefore = &before
+	}
+	if filters.MaxAgeHours > 0 {
+		after := now.Add(-time.Duration(filters.MaxAgeHours) * time.Hour)
+		q.CreatedAfter = &after
+	}
+
+	redemptions, total, err := s.repo.FetchRedemptionQueue(q, page, limit)
+	if err != nil {
+		return nil, types.PaginationMeta{}, err
+	}
+	res := make([]types.RedemptionQueueItem, 0, len(redemptions))
+	for i := range redemptions {
+		res = append(res, *toRedemptionQueueItem(&redemptions[i], now))
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+	return res, types.PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   int(total),
+		ItemsPerPage: limit,
+	}, nil
+}
+
+// ClaimRedemption assigns an open redemption to the admin unless another
+// admin already has it.
+func (s *adminService) ClaimRedemption(adminID, id string) (*types.RedemptionQueueItem, error) {
+	return s.updateQueuedRedemption(id, func(tx *gorm.DB, r *store.Redemption, now time.Time) error {
+		if r.AssignedTo != nil && *r.AssignedTo != adminID {
+			return errors.New("claimed by another admin")
+		}
+		if r.AssignedTo == nil {
+			r.AssignedTo = &adminID
+			r.AssignedAt = &now
+		}
+		return nil
+	})
+}
+
+// AssignRedemption hands an open redemption to another admin, or returns it
+// to the queue when assigneeID is empty.
+func (s *adminService) AssignRedemption(adminID, id, assigneeID string) (*types.RedemptionQueueItem, error) {
+	if assigneeID != "" {
+		assignee, err := s.repo.FindUserByID(assigneeID)
+		if err != nil {
+			return nil, err
+		}
+		if assignee == nil || assignee.Role != "admin" {
+			return nil, errors.New("invalid assignee")
+		}
+	}
+	return s.updateQueuedRedemption(id, func(tx *gorm.DB, r *store.Redemption, now time.Time) error {
+		if assigneeID == "" {
+			r.AssignedTo = nil
+			r.AssignedAt = nil
+			return nil
+		}
+		r.AssignedTo = &assigneeID
+		r.AssignedAt = &now
+		return nil
+	})
+}
+
+// BulkUpdateRedemptions applies the action to each item in its own
+// transaction, so one failing item does not hold back the others.
+func (s *adminService) BulkUpdateRedemptions(adminID string, input types.BulkRedemptionRequest) ([]types.BulkRedemptionResult, error) {
+	transition, ok := redemptionTransitions[input.Action]
+	if !ok {
+		return nil, errors.New("invalid action")
+	}
+
+	results := make([]types.BulkRedemptionResult, 0, len(input.Items))
+	for _, item := range input.Items {
+		result := types.BulkRedemptionResult{ID: item.ID, Status: transition.to}
+		if err := s.transitionRedemption(adminID, item.ID, transition, item.Reason); err != nil {
+			result = types.BulkRedemptionResult{ID: item.ID, Error: "update failed"}
+			if bulkItemErrors[err.Error()] {
+				result.Error = err.Error()
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// UpdateRedemptionStatus moves a single redemption to status through
+// redemptionStatusTransitions.
+func (s *adminService) UpdateRedemptionStatus(adminID, id, status, reason string) error {
+	transition, ok := redemptionStatusTransitions[status]
+	if !ok {
+		return errors.New("invalid status")
+	}
+	return s.transitionRedemption(adminID, id, transition, reason)
+}
+
+// transitionRedemption applies transition to a locked redemption. An
+// unclaimed redemption is claimed by the admin, and one claimed by someone
+// else is left alone. Rejecting and cancelling refund the points, credits and
+// stock.
+func (s *adminService) transitionRedemption(adminID, id string, transition redemptionTransition, reason string) error {
+	_, err := s.updateQueuedRedemption(id, func(tx *gorm.DB, r *store.Redemption, now time.Time) error {
+		if r.AssignedTo != nil && *r.AssignedTo != adminID {
+			return errors.New("claimed by another admin")
+		}
+		if !slices.Contains(transition.from, redemptionStatus(r)) {
+			return errors.New("invalid transition")
+		}
+		switch transition.to {
+		case "rejected":
+			if reason == "" {
+				return errors.New("reason required")
+			}
+			if err := s.refundRedemptionTx(tx, adminID, r, "rejection_return"); err != nil {
+				return err
+			}
+		case "cancelled":
+			if err := s.refundRedemptionTx(tx, adminID, r, "cancellation_return"); err != nil {
+				return err
+			}
+		}
+
+		if r.AssignedTo == nil {
+			r.AssignedTo = &adminID
+			r.AssignedAt = &now
+		}
+		r.Status = transition.to
+		r.StatusReason = reason
+		r.StatusChangedBy = &adminID
+		r.StatusChangedAt = &now
+		return nil
+	})
+	return err
+}
+
+// refundRedemptionTx returns what the redemption took: the points and credits
+// it was paid with, its units to the offer cap, and its units to stock under
+// stockReason.
+func (s *adminService) refundRedemptionTx(tx *gorm.DB, adminID string, r *store.Redemption, stockReason string) error {
+	pointsUsed := r.PointsUsed
+	if pointsUsed == 0 && r.CreditsUsed == 0 {
+		product, err := s.repo.GetProductByID(r.ProductID)
+		if err != nil {
+			return err
+		}
+		if product != nil {
+			pointsUsed = redemptionPointsUsed(r, product)
+		}
+	}
+	if pointsUsed > 0 || r.CreditsUsed > 0 {
+		if err := s.repo.ApplyWalletChangeTx(tx, &store.WalletTransaction{
+			UserID:       r.UserID,
+			PointsDelta:  pointsUsed,
+			CreditsDelta: r.CreditsUsed,
+			Reason:       "redemption_refund",
+			ReferenceID:  r.ID,
+			ActorUserID:  &adminID,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.ReleaseOfferQuantityTx(tx, r.ProductID, r.Quantity, r.CreatedAt); err != nil {
+		return err
+	}
+	return s.repo.ApplyStockChangeTx(tx, &store.InventoryMovement{
+		ProductID:   r.ProductID,
+		VariantID:   r.VariantID,
+		Delta:       r.Quantity,
+		Reason:      stockReason,
+		ReferenceID: r.ID,
+		ActorUserID: &adminID,
+	})
+}
+
+// updateQueuedRedemption locks a redemption that is not closed yet, applies fn
+// and saves it.
+func (s *adminService) updateQueuedRedemption(id string, fn func(tx *gorm.DB, r *store.Redemption, now time.Time) error) (*types.RedemptionQueueItem, error) {
+	err := s.repo.WithTx(func(tx *gorm.DB) error {
+		r, err := s.repo.LockRedemptionTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return errors.New("not found")
+		}
+		if slices.Contains(closedRedemptionStatuses, redemptionStatus(r)) {
+			return errors.New("redemption is closed")
+		}
+		if err := fn(tx, r, time.Now()); err != nil {
+			return err
+		}
+		return s.repo.SaveRedemptionTx(tx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.repo.GetRedemptionWithCategory(id)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	return toRedemptionQueueItem(r, time.Now()), nil
+}
+
+func toRedemptionQueueItem(r *store.Redemption, now time.Time) *types.RedemptionQueueItem {
+	due := r.CreatedAt.Add(redemptionSLA)
+	item := &types.RedemptionQueueItem{
+		RedemptionResponse: *ToRedemptionResponse(r),
+		UserID:             r.UserID,
+		Status:             redemptionStatus(r),
+		AssignedTo:         r.AssignedTo,
+		StatusReason:       r.StatusReason,
+		AgeMinutes:         int(now.Sub(r.CreatedAt).Minutes()),
+		DueAt:              due.Format(time.RFC3339),
+	}
+	item.Overdue = slices.Contains(openRedemptionStatuses, item.Status) && now.After(due)
+	if r.Product.Category.ID != "" {
+		item.Category = &types.CategorySummary{ID: r.Product.Category.ID, Name: r.Product.Category.Name}
+	}
+	if r.AssignedAt != nil {
+		assigned := r.AssignedAt.Format(time.RFC3339)
+		item.AssignedAt = &assigned
+	}
+	return item
+}
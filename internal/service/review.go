@@ -0,0 +1,183 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/store"
+	"Start/internal/types"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxReviewLength = 2000
+
+type reviewService struct {
+	repo *repository.Repository
+}
+
+func NewReviewService(repo *repository.Repository) ReviewService {
+	return &reviewService{repo: repo}
+}
+
+func (s *reviewService) GetProductReviews(productID string, page, limit int) ([]types.ReviewResponse, types.PaginationMeta, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil || !product.IsActive {
+		return nil, types.PaginationMeta{}, errors.New("product not found")
+	}
+
+	reviews, meta, err := s.listReviews(productID, "published", page, limit)
+	for i := range reviews {
+		reviews[i].ModerationNote = ""
+	}
+	return reviews, meta, err
+}
+
+func (s *reviewService) GetAllReviews(productID, status string, page, limit int) ([]types.ReviewResponse, types.PaginationMeta, error) {
+	if status != "" && status != "published" && status != "hidden" {
+		return nil, types.PaginationMeta{}, errors.New("invalid status")
+	}
+	return s.listReviews(productID, status, page, limit)
+}
+
+func (s *reviewService) listReviews(productID, status string, page, limit int) ([]types.ReviewResponse, types.PaginationMeta, error) {
+	reviews, total, err := s.repo.ListReviews(productID, status, page, limit)
+	if err != nil {
+		return nil, types.PaginationMeta{}, err
+	}
+
+	res := make([]types.ReviewResponse, 0, len(reviews))
+	for i := range reviews {
+		res = append(res, *toReviewResponse(&reviews[i]))
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+	return res, types.PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   int(total),
+		ItemsPerPage: limit,
+	}, nil
+}
+
+// CreateReview accepts one review per user and product, and only from users
+// who have had a redemption of the product delivered.
+func (s *reviewService) CreateReview(userID, productID string, input types.ReviewRequest) (*types.ReviewResponse, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil || !product.IsActive {
+		return nil, errors.New("product not found")
+	}
+	body, err := validateReview(input)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := s.repo.HasDeliveredRedemption(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !verified {
+		return nil, errors.New("review requires a delivered redemption")
+	}
+	if existing, err := s.repo.FindReviewByAuthor(productID, userID); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, errors.New("already reviewed")
+	}
+
+	now := time.Now()
+	rv := &store.ProductReview{
+		ID:        uuid.NewString(),
+		ProductID: productID,
+		UserID:    userID,
+		Rating:    input.Rating,
+		Body:      body,
+		Status:    "published",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateReview(rv); err != nil {
+		return nil, err
+	}
+	return s.reload(rv.ID)
+}
+
+func (s *reviewService) UpdateReview(userID, productID, reviewID string, input types.ReviewRequest) (*types.ReviewResponse, error) {
+	rv, err := s.repo.GetReviewByID(reviewID)
+	if err != nil || rv == nil || rv.ProductID != productID {
+		return nil, errors.New("review not found")
+	}
+	if rv.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+	body, err := validateReview(input)
+	if err != nil {
+		return nil, err
+	}
+
+	rv.Rating = input.Rating
+	rv.Body = body
+	rv.UpdatedAt = time.Now()
+	if err := s.repo.UpdateReview(rv); err != nil {
+		return nil, err
+	}
+	return toReviewResponse(rv), nil
+}
+
+func (s *reviewService) ModerateReview(adminID, reviewID string, input types.ModerateReviewRequest) (*types.ReviewResponse, error) {
+	if input.Status != "published" && input.Status != "hidden" {
+		return nil, errors.New("invalid status")
+	}
+	rv, err := s.repo.GetReviewByID(reviewID)
+	if err != nil || rv == nil {
+		return nil, errors.New("review not found")
+	}
+
+	rv.Status = input.Status
+	rv.ModerationNote = strings.TrimSpace(input.Note)
+	rv.ModeratedBy = &adminID
+	rv.UpdatedAt = time.Now()
+	if err := s.repo.UpdateReview(rv); err != nil {
+		return nil, err
+	}
+	return toReviewResponse(rv), nil
+}
+
+func (s *reviewService) reload(id string) (*types.ReviewResponse, error) {
+	rv, err := s.repo.GetReviewByID(id)
+	if err != nil || rv == nil {
+		return nil, errors.New("review not found")
+	}
+	return toReviewResponse(rv), nil
+}
+
+func validateReview(input types.ReviewRequest) (string, error) {
+	if input.Rating < 1 || input.Rating > 5 {
+		return "", errors.New("invalid rating")
+	}
+	body := strings.TrimSpace(input.Body)
+	if utf8.RuneCountInString(body) > maxReviewLength {
+		return "", errors.New("review is too long")
+	}
+	return body, nil
+}
+
+func toReviewResponse(rv *store.ProductReview) *types.ReviewResponse {
+	author := rv.User.FirstName
+	if initial, _ := utf8.DecodeRuneInString(rv.User.LastName); initial != utf8.RuneError {
+		author += " " + string(initial) + "."
+	}
+	return &types.ReviewResponse{
+		ID:             rv.ID,
+		ProductID:      rv.ProductID,
+		AuthorID:       rv.UserID,
+		AuthorName:     strings.TrimSpace(author),
+		Rating:         rv.Rating,
+		Body:           rv.Body,
+		Status:         rv.Status,
+		ModerationNote: rv.ModerationNote,
+		CreatedAt:      rv.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      rv.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,656 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/shared/payment"
+	"Start/internal/store"
+	"Start/internal/types"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"math"
+	"time"
+)
+
+const (
+	maxRenewalAttempts   = 3
+	renewalRetryDelay    = 24 * time.Hour
+	pendingChargeTimeout = 10 * time.Minute
+)
+
+var errPaymentFailed = errors.New("payment failed")
+
+type subscriptionService struct {
+	repo     *repository.Repository
+	payments payment.Provider
+}
+
+func NewSubscriptionService(repo *repository.Repository, payments payment.Provider) SubscriptionService {
+	return &subscriptionService{repo: repo, payments: payments}
+}
+
+func (s *subscriptionService) GetPlans() ([]types.SubscriptionPlanResponse, error) {
+	plans, err := s.repo.ListSubscriptionPlans(true)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]types.SubscriptionPlanResponse, 0, len(plans))
+	for i := range plans {
+		res = append(res, *toSubscriptionPlanResponse(&plans[i]))
+	}
+	return res, nil
+}
+
+func (s *subscriptionService) CreatePlan(input *types.SubscriptionPlanRequest) (*types.SubscriptionPlanResponse, error) {
+	if err := validatePlan(input); err != nil {
+		return nil, err
+	}
+	plan := &store.SubscriptionPlan{
+		ID:           uuid.NewString(),
+		Name:         input.Name,
+		PriceEGP:     input.PriceEGP,
+		Credits:      input.Credits,
+		RewardPoints: input.RewardPoints,
+		TrialDays:    input.TrialDays,
+		IsActive:     input.IsActive == nil || *input.IsActive,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.repo.CreateSubscriptionPlan(plan); err != nil {
+		return nil, err
+	}
+	return toSubscriptionPlanResponse(plan), nil
+}
+
+// UpdatePlan changes what the plan's subscribers get from their next renewal
+// on; periods that were already billed are not adjusted.
+func (s *subscriptionService) UpdatePlan(id string, input *types.SubscriptionPlanRequest) (*types.SubscriptionPlanResponse, error) {
+	plan, err := s.repo.GetSubscriptionPlanByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, errors.New("plan not found")
+	}
+	if err := validatePlan(input); err != nil {
+		return nil, err
+	}
+
+	plan.Name = input.Name
+	plan.PriceEGP = input.PriceEGP
+	plan.Credits = input.Credits
+	plan.RewardPoints = input.RewardPoints
+	plan.TrialDays = input.TrialDays
+	if input.IsActive != nil {
+		plan.IsActive = *input.IsActive
+	}
+	if err := s.repo.UpdateSubscriptionPlan(plan); err != nil {
+		return nil, err
+	}
+	return toSubscriptionPlanResponse(plan), nil
+}
+
+func validatePlan(input *types.SubscriptionPlanRequest) error {
+	if input.PriceEGP <= 0 || input.Credits <= 0 || input.RewardPoints < 0 || input.TrialDays < 0 {
+		return errors.New("invalid plan")
+	}
+	return nil
+}
+
+func (s *subscriptionService) GetSubscriptions(userID string) ([]types.SubscriptionResponse, error) {
+	subs, err := s.repo.ListUserSubscriptions(userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]types.SubscriptionResponse, 0, len(subs))
+	for i := range subs {
+		res = append(res, *toSubscriptionResponse(&subs[i]))
+	}
+	return res, nil
+}
+
+// Subscribe starts a subscription. Plans with a trial start in the trialing
+// state and are first charged when the trial ends, unless the user has had a
+// subscription before; otherwise the first month is charged right away.
+func (s *subscriptionService) Subscribe(userID string, input *types.SubscribeRequest) (*types.SubscriptionResponse, error) {
+	plan, err := s.repo.GetSubscriptionPlanByID(input.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil || !plan.IsActive {
+		return nil, errors.New("plan not found")
+	}
+	previous, err := s.repo.ListUserSubscriptions(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range previous {
+		if p.Status != "cancelled" {
+			return nil, errors.New("already subscribed")
+		}
+	}
+
+	now := time.Now()
+	sub := &store.Subscription{
+		ID:                 uuid.NewString(),
+		UserID:             userID,
+		PlanID:             plan.ID,
+		PaymentMethod:      input.PaymentMethod,
+		CurrentPeriodStart: now,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if plan.TrialDays > 0 && len(previous) == 0 {
+		sub.Status = "trialing"
+		sub.CurrentPeriodEnd = now.AddDate(0, 0, plan.TrialDays)
+	} else {
+		sub.Status = "incomplete"
+		sub.CurrentPeriodEnd = billingPeriodEnd(now)
+	}
+	sub.RenewsAt = sub.CurrentPeriodEnd
+
+	var p *store.Purchase
+	if err := s.repo.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Omit("Plan").Create(sub).Error; err != nil {
+			return err
+		}
+		if sub.Status == "trialing" {
+			return nil
+		}
+		// A new subscription has no balance, so billing leaves sub unchanged.
+		var err error
+		p, err = s.startBillingTx(tx, sub, plan.ID, &now, plan.PriceEGP, plan.Credits, plan.RewardPoints, periodReference(sub, now), now)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if p != nil {
+		if sub, err = s.completeBilling(p, sub.PaymentMethod); err != nil {
+			return nil, err
+		}
+	}
+	sub.Plan = *plan
+	return toSubscriptionResponse(sub), nil
+}
+
+// ChangePlan moves an active subscription to another plan for the rest of the
+// current period. An upgrade charges the price difference for the remaining
+// share of the period and grants the same share of the extra credits and
+// points; a downgrade credits the unused difference to the balance that the
+// next charge draws from. Trialing subscriptions switch without proration.
+func (s *subscriptionService) ChangePlan(userID, id, planID string) (*types.SubscriptionResponse, error) {
+	newPlan, err := s.repo.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		return nil, err
+	}
+	if newPlan == nil || !newPlan.IsActive {
+		return nil, errors.New("plan not found")
+	}
+
+	var sub *store.Subscription
+	var p *store.Purchase
+	err = s.repo.WithTx(func(tx *gorm.DB) error {
+		if sub, err = s.lockOwnedTx(tx, userID, id); err != nil {
+			return err
+		}
+		if sub.PlanID == newPlan.ID {
+			return errors.New("already on this plan")
+		}
+
+		now := time.Now()
+		switch sub.Status {
+		case "trialing":
+		case "active":
+			oldPlan, err := s.repo.GetSubscriptionPlanByID(sub.PlanID)
+			if err != nil {
+				return err
+			}
+			if oldPlan == nil {
+				oldPlan = &store.SubscriptionPlan{}
+			}
+			share := remainingShare(sub, now)
+			diff := roundEGP((newPlan.PriceEGP - oldPlan.PriceEGP) * share)
+			if diff > 0 {
+				// The switch happens when the charge settles.
+				credits := int(math.Max(0, float64(newPlan.Credits-oldPlan.Credits)*share))
+				points := int(math.Max(0, float64(newPlan.RewardPoints-oldPlan.RewardPoints)*share))
+				if p, err = s.startBillingTx(tx, sub, newPlan.ID, nil, diff, credits, points, sub.ID+":change:"+uuid.NewString(), now); err != nil {
+					return err
+				}
+				sub.UpdatedAt = now
+				return s.repo.SaveSubscriptionTx(tx, sub)
+			}
+			sub.BalanceEGP = roundEGP(sub.BalanceEGP - diff)
+		default:
+			return errors.New("invalid subscription state")
+		}
+
+		sub.PlanID = newPlan.ID
+		sub.UpdatedAt = now
+		return s.repo.SaveSubscriptionTx(tx, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if p != nil {
+		if sub, err = s.completeBilling(p, sub.PaymentMethod); err != nil {
+			return nil, err
+		}
+	}
+	sub.Plan = *newPlan
+	return toSubscriptionResponse(sub), nil
+}
+
+// Pause stops renewals of an active subscription. The period that was
+// already paid for stays usable.
+func (s *subscriptionService) Pause(userID, id string) (*types.SubscriptionResponse, error) {
+	return s.update(userID, id, func(tx *gorm.DB, sub *store.Subscription, now time.Time) error {
+		if sub.Status != "active" {
+			return errors.New("invalid subscription state")
+		}
+		sub.Status = "paused"
+		sub.PausedAt = &now
+		return nil
+	})
+}
+
+// Resume reactivates a paused subscription. Inside the paid period it simply
+// renews at the period end again; after it, a new period is charged now and
+// the subscription stays paused unless the charge goes through.
+func (s *subscriptionService) Resume(userID, id string) (*types.SubscriptionResponse, error) {
+	var p *store.Purchase
+	var method string
+	res, err := s.update(userID, id, func(tx *gorm.DB, sub *store.Subscription, now time.Time) error {
+		if sub.Status != "paused" {
+			return errors.New("invalid subscription state")
+		}
+		if now.After(sub.CurrentPeriodEnd) {
+			plan, err := s.repo.GetSubscriptionPlanByID(sub.PlanID)
+			if err != nil {
+				return err
+			}
+			if plan == nil {
+				return errors.New("plan not found")
+			}
+			method = sub.PaymentMethod
+			p, err = s.startBillingTx(tx, sub, plan.ID, &now, plan.PriceEGP, plan.Credits, plan.RewardPoints, periodReference(sub, now), now)
+			return err
+		}
+		sub.Status = "active"
+		sub.PausedAt = nil
+		sub.RenewsAt = sub.CurrentPeriodEnd
+		return nil
+	})
+	if err != nil || p == nil {
+		return res, err
+	}
+
+	sub, err := s.completeBilling(p, method)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponseWithPlan(sub), nil
+}
+
+// Cancel ends an active subscription when its paid period runs out, or right
+// away when asked to. Trialing, paused and past-due subscriptions are always
+// cancelled immediately. Nothing is refunded.
+func (s *subscriptionService) Cancel(userID, id string, immediately bool) (*types.SubscriptionResponse, error) {
+	return s.update(userID, id, func(tx *gorm.DB, sub *store.Subscription, now time.Time) error {
+		if sub.Status == "cancelled" {
+			return errors.New("invalid subscription state")
+		}
+		if sub.Status == "active" && !immediately {
+			sub.CancelAtPeriodEnd = true
+			return nil
+		}
+		sub.Status = "cancelled"
+		sub.CancelledAt = &now
+		return nil
+	})
+}
+
+// ProcessDueRenewals renews every subscription whose renewal time has passed
+// and returns how many were processed. Charges left pending by an earlier run
+// are settled first. A renewal that fails for any reason other than a
+// declined payment is logged and skipped until the next run.
+func (s *subscriptionService) ProcessDueRenewals(now time.Time) (int, error) {
+	s.settleStalePurchases(now)
+
+	processed := 0
+	var skipped []string
+	for {
+		var sub *store.Subscription
+		var p *store.Purchase
+		err := s.repo.WithTx(func(tx *gorm.DB) error {
+			var err error
+			if sub, err = s.repo.ClaimDueSubscriptionTx(tx, now, skipped); err != nil || sub == nil {
+				return err
+			}
+			p, err = s.renewTx(tx, sub, now)
+			return err
+		})
+		if sub == nil {
+			return processed, err
+		}
+		if err == nil && p != nil {
+			if _, err = s.completeBilling(p, sub.PaymentMethod); errors.Is(err, errPaymentFailed) {
+				err = nil
+			}
+		}
+		if err != nil {
+			log.Printf("Renewing subscription %s failed: %v", sub.ID, err)
+			skipped = append(skipped, sub.ID)
+			continue
+		}
+		processed++
+	}
+}
+
+// settleStalePurchases retries charges whose settlement never happened. The
+// provider recognises the reference, so a charge that did go through is not
+// taken again.
+func (s *subscriptionService) settleStalePurchases(now time.Time) {
+	stale, err := s.repo.ListStalePendingPurchases(now.Add(-pendingChargeTimeout))
+	if err != nil {
+		log.Printf("Listing pending subscription charges failed: %v", err)
+		return
+	}
+	for i := range stale {
+		p := &stale[i]
+		sub, err := s.repo.GetSubscriptionByID(*p.SubscriptionID)
+		if err == nil && sub == nil {
+			err = errors.New("subscription not found")
+		}
+		if err == nil {
+			if _, err = s.completeBilling(p, sub.PaymentMethod); errors.Is(err, errPaymentFailed) {
+				err = nil
+			}
+		}
+		if err != nil {
+			log.Printf("Settling subscription charge %s failed: %v", p.ID, err)
+		}
+	}
+}
+
+// renewTx starts billing the next period of a due subscription, or cancels it
+// when it was set to end with its period. The charge is settled by
+// completeBilling once tx has committed.
+func (s *subscriptionService) renewTx(tx *gorm.DB, sub *store.Subscription, now time.Time) (*store.Purchase, error) {
+	sub.UpdatedAt = now
+
+	plan, err := s.repo.GetSubscriptionPlanByID(sub.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil || (sub.CancelAtPeriodEnd && sub.Status != "past_due") {
+		sub.Status = "cancelled"
+		sub.CancelledAt = &now
+		return nil, s.repo.SaveSubscriptionTx(tx, sub)
+	}
+
+	// A retried or long-overdue renewal starts its period now rather than
+	// billing the missed months one after another.
+	start := sub.CurrentPeriodEnd
+	if sub.Status == "past_due" || !billingPeriodEnd(start).After(now) {
+		start = now
+	}
+
+	p, err := s.startBillingTx(tx, sub, plan.ID, &start, plan.PriceEGP, plan.Credits, plan.RewardPoints, periodReference(sub, start), now)
+	if err != nil {
+		return nil, err
+	}
+	return p, s.repo.SaveSubscriptionTx(tx, sub)
+}
+
+// startBillingTx records a pending purchase for amount, less whatever the
+// subscription's balance covers, and takes that share off the balance; the
+// caller saves sub. Nothing is charged until completeBilling runs after tx
+// commits, so no payment call is made while rows are locked.
+func (s *subscriptionService) startBillingTx(tx *gorm.DB, sub *store.Subscription, planID string, periodStart *time.Time, amount float64, credits, points int, reference string, now time.Time) (*store.Purchase, error) {
+	fromBalance := math.Min(sub.BalanceEGP, amount)
+	sub.BalanceEGP = roundEGP(sub.BalanceEGP - fromBalance)
+
+	p := &store.Purchase{
+		ID:                 uuid.NewString(),
+		UserID:             sub.UserID,
+		Status:             "pending",
+		Credits:            credits,
+		CreatedAt:          now,
+		PriceEGP:           roundEGP(amount - fromBalance),
+		RewardPoints:       points,
+		SubscriptionID:     &sub.ID,
+		PaymentReference:   reference,
+		SubscriptionPlanID: &planID,
+		PeriodStart:        periodStart,
+		BalanceAppliedEGP:  fromBalance,
+	}
+	if err := tx.Create(p).Error; err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// completeBilling charges a pending purchase and settles it. A declined
+// payment returns errPaymentFailed; any other provider error leaves the
+// purchase pending for settleStalePurchases to retry.
+func (s *subscriptionService) completeBilling(p *store.Purchase, method string) (*store.Subscription, error) {
+	var txnID string
+	if p.PriceEGP > 0 {
+		var err error
+		txnID, err = s.payments.Charge(context.Background(), payment.ChargeRequest{
+			Reference: p.PaymentReference,
+			UserID:    p.UserID,
+			AmountEGP: p.PriceEGP,
+			Method:    method,
+		})
+		if err != nil && !errors.Is(err, payment.ErrDeclined) {
+			return nil, err
+		}
+		if err != nil {
+			return s.settleBilling(p, "", false)
+		}
+	}
+	return s.settleBilling(p, txnID, true)
+}
+
+// settleBilling completes or fails a pending purchase and applies the outcome
+// to its subscription. A paid charge credits the wallet and moves the
+// subscription to the purchase's plan and period. A declined one returns the
+// balance it used: a new subscription is cancelled, a renewal goes past due
+// and is retried a day later until maxRenewalAttempts, and a plan change or
+// resume leaves the subscription as it was.
+func (s *subscriptionService) settleBilling(p *store.Purchase, txnID string, paid bool) (*store.Subscription, error) {
+	var sub *store.Subscription
+	err := s.repo.WithTx(func(tx *gorm.DB) error {
+		var err error
+		if sub, err = s.repo.LockSubscriptionTx(tx, *p.SubscriptionID); err != nil {
+			return err
+		}
+		if sub == nil {
+			return errors.New("subscription not found")
+		}
+
+		status := "completed"
+		if !paid {
+			status = "failed"
+		}
+		settled, err := s.repo.SettlePurchaseTx(tx, p.ID, status, txnID)
+		if err != nil {
+			return err
+		}
+		if !settled {
+			current, err := s.repo.GetPurchaseByID(p.ID)
+			if err != nil {
+				return err
+			}
+			paid = current.Status == "completed"
+			return nil
+		}
+
+		now := time.Now()
+		sub.UpdatedAt = now
+		if !paid {
+			sub.BalanceEGP = roundEGP(sub.BalanceEGP + p.BalanceAppliedEGP)
+			switch {
+			case sub.Status == "incomplete":
+				sub.Status = "cancelled"
+				sub.CancelledAt = &now
+			case p.PeriodStart != nil && sub.Status != "paused":
+				sub.FailedAttempts++
+				if sub.FailedAttempts >= maxRenewalAttempts {
+					sub.Status = "cancelled"
+					sub.CancelledAt = &now
+				} else {
+					sub.Status = "past_due"
+					sub.RenewsAt = now.Add(renewalRetryDelay)
+				}
+			}
+			return s.repo.SaveSubscriptionTx(tx, sub)
+		}
+
+		if err := s.repo.ApplyWalletChangeTx(tx, &store.WalletTransaction{
+			UserID:       sub.UserID,
+			CreditsDelta: p.Credits,
+			PointsDelta:  p.RewardPoints,
+			Reason:       "subscription",
+			ReferenceID:  p.ID,
+		}); err != nil {
+			return err
+		}
+		if p.SubscriptionPlanID != nil {
+			sub.PlanID = *p.SubscriptionPlanID
+		}
+		if p.PeriodStart != nil {
+			sub.Status = "active"
+			sub.FailedAttempts = 0
+			sub.PausedAt = nil
+			sub.CurrentPeriodStart = *p.PeriodStart
+			sub.CurrentPeriodEnd = billingPeriodEnd(*p.PeriodStart)
+			sub.RenewsAt = sub.CurrentPeriodEnd
+		}
+		return s.repo.SaveSubscriptionTx(tx, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !paid {
+		return nil, errPaymentFailed
+	}
+	return sub, nil
+}
+
+func (s *subscriptionService) update(userID, id string, fn func(tx *gorm.DB, sub *store.Subscription, now time.Time) error) (*types.SubscriptionResponse, error) {
+	var sub *store.Subscription
+	err := s.repo.WithTx(func(tx *gorm.DB) error {
+		var err error
+		if sub, err = s.lockOwnedTx(tx, userID, id); err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := fn(tx, sub, now); err != nil {
+			return err
+		}
+		sub.UpdatedAt = now
+		return s.repo.SaveSubscriptionTx(tx, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponseWithPlan(sub), nil
+}
+
+func (s *subscriptionService) toResponseWithPlan(sub *store.Subscription) *types.SubscriptionResponse {
+	if plan, err := s.repo.GetSubscriptionPlanByID(sub.PlanID); err == nil && plan != nil {
+		sub.Plan = *plan
+	}
+	return toSubscriptionResponse(sub)
+}
+
+func (s *subscriptionService) lockOwnedTx(tx *gorm.DB, userID, id string) (*store.Subscription, error) {
+	sub, err := s.repo.LockSubscriptionTx(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil || sub.UserID != userID {
+		return nil, errors.New("subscription not found")
+	}
+	pending, err := s.repo.HasPendingSubscriptionPurchaseTx(tx, sub.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("payment in progress")
+	}
+	return sub, nil
+}
+
+func billingPeriodEnd(start time.Time) time.Time {
+	return start.AddDate(0, 1, 0)
+}
+
+// periodReference identifies the charge for the period starting at start, so
+// a provider can recognise a retried renewal of the same period.
+func periodReference(sub *store.Subscription, start time.Time) string {
+	return sub.ID + ":" + start.UTC().Format(time.RFC3339)
+}
+
+// remainingShare is the part of the current period that has not elapsed yet.
+func remainingShare(sub *store.Subscription, now time.Time) float64 {
+	total := sub.CurrentPeriodEnd.Sub(sub.CurrentPeriodStart)
+	if total <= 0 {
+		return 0
+	}
+	share := float64(sub.CurrentPeriodEnd.Sub(now)) / float64(total)
+	return math.Max(0, math.Min(1, share))
+}
+
+func roundEGP(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func toSubscriptionPlanResponse(p *store.SubscriptionPlan) *types.SubscriptionPlanResponse {
+	return &types.SubscriptionPlanResponse{
+		ID:           p.ID,
+		Name:         p.Name,
+		PriceEGP:     p.PriceEGP,
+		Credits:      p.Credits,
+		RewardPoints: p.RewardPoints,
+		TrialDays:    p.TrialDays,
+		IsActive:     p.IsActive,
+		CreatedAt:    p.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toSubscriptionResponse(sub *store.Subscription) *types.SubscriptionResponse {
+	res := &types.SubscriptionResponse{
+		ID:                 sub.ID,
+		Plan:               *toSubscriptionPlanResponse(&sub.Plan),
+		Status:             sub.Status,
+		CurrentPeriodStart: sub.CurrentPeriodStart.Format(time.RFC3339),
+		CurrentPeriodEnd:   sub.CurrentPeriodEnd.Format(time.RFC3339),
+		CancelAtPeriodEnd:  sub.CancelAtPeriodEnd,
+		BalanceEGP:         sub.BalanceEGP,
+		FailedAttempts:     sub.FailedAttempts,
+		CreatedAt:          sub.CreatedAt.Format(time.RFC3339),
+	}
+	switch sub.Status {
+	case "trialing", "active", "past_due":
+		if !sub.CancelAtPeriodEnd {
+			renewsAt := sub.RenewsAt.Format(time.RFC3339)
+			res.RenewsAt = &renewsAt
+		}
+	}
+	if sub.PausedAt != nil {
+		pausedAt := sub.PausedAt.Format(time.RFC3339)
+		res.PausedAt = &pausedAt
+	}
+	if sub.CancelledAt != nil {
+		cancelledAt := sub.CancelledAt.Format(time.RFC3339)
+		res.CancelledAt = &cancelledAt
+	}
+	return res
+}
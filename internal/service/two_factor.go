@@ -0,0 +1,223 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/shared/utils"
+	"Start/internal/types"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+const recoveryCodeCount = 10
+
+func (s *authService) CompleteMFALogin(input types.MFALoginInput, clientIP string) (*types.LoginResponse, error) {
+	userID, challengeID, err := utils.ParseMFAChallengeToken(input.ChallengeToken)
+	if err != nil {
+		return nil, errors.New("invalid challenge")
+	}
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil || !user.TOTPEnabled || user.MFAChallengeID != challengeID {
+		return nil, errors.New("invalid challenge")
+	}
+
+	now := time.Now()
+	if err := s.throttle.Attempt(user.Email, clientIP, now); err != nil {
+		return nil, err
+	}
+
+	var verifyErr error
+	switch {
+	case input.Code != "":
+		verifyErr = s.consumeTOTPCode(userID, user.TOTPSecret, input.Code)
+	case input.RecoveryCode != "":
+		ok, err := s.consumeRecoveryCode(userID, input.RecoveryCode)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			verifyErr = errors.New("invalid code")
+		}
+	default:
+		verifyErr = errors.New("invalid code")
+	}
+
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	consumed, err := s.repo.ConsumeMFAChallenge(userID, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, errors.New("invalid challenge")
+	}
+
+	_ = s.throttle.Succeed(user.Email, clientIP)
+	return issueLoginResponse(user)
+}
+
+// issueMFAChallenge answers a login that still needs a second factor with a
+// challenge token that replaces any earlier one and works once.
+func issueMFAChallenge(repo *repository.Repository, userID string) (*types.LoginResponse, error) {
+	challengeID := uuid.NewString()
+	if err := repo.SetMFAChallenge(userID, challengeID); err != nil {
+		return nil, err
+	}
+	challenge, err := utils.GenerateMFAChallengeToken(userID, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.LoginResponse{MFARequired: true, ChallengeToken: challenge}, nil
+}
+
+func (s *authService) BeginTOTPEnrollment(userID string) (*types.TOTPEnrollmentResponse, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetPendingTOTPSecret(userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &types.TOTPEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(user.Email, secret),
+	}, nil
+}
+
+func (s *authService) ConfirmTOTPEnrollment(userID, code string) (*types.TOTPActivationResponse, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("enrollment not started")
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, errors.New("invalid code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.EnableTOTP(userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	access, refresh, err := utils.GenerateTokens(user.ID, user.Email, user.Role, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TOTPActivationResponse{
+		RecoveryCodes: codes,
+		Tokens: types.TokenPair{
+			AccessToken:  access,
+			RefreshToken: refresh,
+		},
+	}, nil
+}
+
+func (s *authService) DisableTOTP(userID, password, code string) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return errors.New("two-factor authentication not enabled")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return errors.New("incorrect password")
+	}
+	if err := s.consumeTOTPCode(userID, user.TOTPSecret, code); err != nil {
+		return err
+	}
+
+	return s.repo.DisableTOTP(userID)
+}
+
+func (s *authService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication not enabled")
+	}
+	if err := s.consumeTOTPCode(userID, user.TOTPSecret, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// consumeRecoveryCode compares the code against the user's unused codes one
+// by one, since salted hashes cannot be looked up directly.
+func (s *authService) consumeRecoveryCode(userID, code string) (bool, error) {
+	codes, err := s.repo.ListUnusedRecoveryCodes(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range codes {
+		if utils.VerifyRecoveryCode(code, c.CodeHash) {
+			return s.repo.MarkRecoveryCodeUsed(c.ID)
+		}
+	}
+	return false, nil
+}
+
+func (s *authService) consumeTOTPCode(userID, secret, code string) error {
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return errors.New("invalid code")
+	}
+
+	fresh, err := s.repo.ConsumeTOTPStep(userID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return errors.New("invalid code")
+	}
+	return nil
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		if hashes[i], err = utils.HashRecoveryCode(c); err != nil {
+			return nil, nil, err
+		}
+	}
+	return codes, hashes, nil
+}
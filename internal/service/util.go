@@ -9,19 +9,25 @@ import (
 )
 
 func ToPurchaseResponse(p *store.Purchase, pkg *store.CreditPackage) *types.PurchaseResponse {
-	return &types.PurchaseResponse{
+	res := &types.PurchaseResponse{
 		ID:              p.ID,
 		UserID:          p.UserID,
 		CreditPackageID: p.CreditPackageID,
 		Status:          p.Status,
 		Credits:         p.Credits,
+		RewardPoints:    p.RewardPoints,
 		CreatedAt:       p.CreatedAt.Format(time.RFC3339),
-		CreditPackageInfo: &types.SimplePackageInfo{
+		SubscriptionID:  p.SubscriptionID,
+		PriceEGP:        p.PriceEGP,
+	}
+	if p.CreditPackageID != nil {
+		res.CreditPackageInfo = &types.SimplePackageInfo{
 			ID:    pkg.ID,
 			Name:  pkg.Name,
-			Price: pkg.PriceEGP,
-		},
+			Price: p.PriceEGP,
+		}
 	}
+	return res
 }
 
 func ToProductResponse(p *store.Product, c *store.Category) *types.ProductResponse {
@@ -36,17 +42,56 @@ func ToProductResponse(p *store.Product, c *store.Category) *types.ProductRespon
 	}
 
 	return &types.ProductResponse{
-		ID:               p.ID,
-		Name:             p.Name,
-		Description:      p.Description,
-		Category:         &types.CategorySummary{ID: c.ID, Name: c.Name},
-		RedemptionPoints: p.RedemptionPoints,
-		StockQuantity:    p.StockQuantity,
-		IsOffer:          p.IsOffer,
-		ImageURL:         image,
-		Tags:             tags,
-		CreatedAt:        p.CreatedAt.Format(time.RFC3339),
+		ID:                p.ID,
+		Name:              p.Name,
+		Description:       p.Description,
+		Category:          &types.CategorySummary{ID: c.ID, Name: c.Name},
+		RedemptionPoints:  p.RedemptionPoints,
+		StockQuantity:     p.StockQuantity,
+		IsOffer:           p.IsOffer,
+		IsActive:          p.IsActive,
+		ImageURL:          image,
+		Tags:              tags,
+		CreatedAt:         p.CreatedAt.Format(time.RFC3339),
+		Offer:             toOfferResponse(p, time.Now()),
+		Variants:          toVariantResponses(p, p.Variants),
+		LowStockThreshold: p.LowStockThreshold,
+		Rating:            types.RatingSummary{Average: p.RatingAverage, Count: p.RatingCount},
+		Translations:      decodeTranslations(p.Translations),
+		CreditPayment:     toCreditPaymentRule(p),
+		RedemptionLimits:  toRedemptionLimits(p),
+	}
+}
+
+func toCreditPaymentRule(p *store.Product) *types.CreditPaymentRule {
+	if !p.AllowCreditPayment {
+		return nil
+	}
+	return &types.CreditPaymentRule{Allowed: true, MaxSharePercent: p.MaxCreditShare}
+}
+
+func toVariantResponses(p *store.Product, variants []store.ProductVariant) []types.VariantResponse {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	res := make([]types.VariantResponse, 0, len(variants))
+	for i := range variants {
+		v := &variants[i]
+		var options map[string]string
+		if err := json.Unmarshal(v.Options, &options); err != nil {
+			options = map[string]string{}
+		}
+		res = append(res, types.VariantResponse{
+			ID:               v.ID,
+			SKU:              v.SKU,
+			Options:          options,
+			StockQuantity:    v.StockQuantity,
+			RedemptionPoints: unitPoints(p, v, now),
+		})
 	}
+	return res
 }
 
 func HumanizeNumber(n int) string {
@@ -70,15 +115,63 @@ func ToUserResponse(u *store.User) *types.UserDTO {
 	}
 }
 
+// redemptionPointsUsed is what the redemption took from the points balance.
+// Redemptions made before the price was recorded fall back to the product's
+// current points.
+func redemptionPointsUsed(r *store.Redemption, p *store.Product) int {
+	if r.PointsUsed == 0 && r.CreditsUsed == 0 {
+		return r.Quantity * p.RedemptionPoints
+	}
+	return r.PointsUsed
+}
+
 func ToRedemptionResponse(r *store.Redemption) *types.RedemptionResponse {
+	pointsUsed := redemptionPointsUsed(r, &r.Product)
+
 	return &types.RedemptionResponse{
 		ID: r.ID,
 		Product: types.RedemptionProduct{
-			ID:   r.Product.ID,
-			Name: r.Product.Name,
+			ID:           r.Product.ID,
+			Name:         r.Product.Name,
+			RewardPoints: r.Product.RedemptionPoints,
 		},
+		VariantID:  r.VariantID,
 		Quantity:   r.Quantity,
-		PointsUsed: r.Quantity * r.Product.RedemptionPoints,
+		PointsUsed: pointsUsed,
 		CreatedAt:  r.CreatedAt.Format(time.RFC3339),
+
+		CreditsUsed:     r.CreditsUsed,
+		PointsPerCredit: r.PointsPerCredit,
+		OrderID:         r.OrderID,
+	}
+}
+
+func ToAPIKeyResponse(k *store.APIKey) *types.APIKeyResponse {
+	var scopes, allowed []string
+	if err := json.Unmarshal(k.Scopes, &scopes); err != nil {
+		scopes = []string{}
+	}
+	if err := json.Unmarshal(k.AllowedIPs, &allowed); err != nil {
+		allowed = []string{}
+	}
+
+	res := &types.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		AllowedIPs: allowed,
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  k.CreatedAt.Format(time.RFC3339),
+		LastUsedIP: k.LastUsedIP,
+	}
+	if k.LastUsedAt != nil {
+		t := k.LastUsedAt.Format(time.RFC3339)
+		res.LastUsedAt = &t
+	}
+	if k.RevokedAt != nil {
+		t := k.RevokedAt.Format(time.RFC3339)
+		res.RevokedAt = &t
 	}
+	return res
 }
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"Start/internal/repository"
+	"Start/internal/types"
+	"errors"
+	"time"
+)
+
+type wishlistService struct {
+	repo *repository.Repository
+}
+
+func NewWishlistService(repo *repository.Repository) WishlistService {
+	return &wishlistService{repo: repo}
+}
+
+func (s *wishlistService) GetWishlist(userID string, page, limit int) ([]types.WishlistItemResponse, types.PaginationMeta, error) {
+	items, total, err := s.repo.ListWishlistItems(userID, page, limit)
+	if err != nil {
+		return nil, types.PaginationMeta{}, err
+	}
+
+	balance := 0
+	if wallet, err := s.repo.GetWalletByUserID(userID); err == nil && wallet != nil {
+		balance = wallet.PointsBalance
+	}
+
+	now := time.Now()
+	res := make([]types.WishlistItemResponse, 0, len(items))
+	for i := range items {
+		p := &items[i].Product
+		res = append(res, types.WishlistItemResponse{
+			Product:    ToProductResponse(p, &p.Category),
+			InStock:    p.IsActive && p.StockQuantity > 0,
+			Affordable: balance >= unitPoints(p, nil, now),
+			AddedAt:    items[i].CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+	return res, types.PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   int(total),
+		ItemsPerPage: limit,
+	}, nil
+}
+
+func (s *wishlistService) AddToWishlist(userID, productID string) error {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil || product == nil || !product.IsActive {
+		return errors.New("product not found")
+	}
+	return s.repo.AddWishlistItem(userID, productID)
+}
+
+func (s *wishlistService) RemoveFromWishlist(userID, productID string) error {
+	removed, err := s.repo.RemoveWishlistItem(userID, productID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("not found")
+	}
+	return nil
+}
+
+func (s *wishlistService) GetNotifications(userID string, unreadOnly bool, page, limit int) ([]types.NotificationResponse, types.PaginationMeta, error) {
+	notifications, total, err := s.repo.ListNotifications(userID, unreadOnly, page, limit)
+	if err != nil {
+		return nil, types.PaginationMeta{}, err
+	}
+
+	res := make([]types.NotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		item := types.NotificationResponse{
+			ID:        n.ID,
+			Kind:      n.Kind,
+			ProductID: n.ProductID,
+			Message:   n.Message,
+			CreatedAt: n.CreatedAt.Format(time.RFC3339),
+		}
+		if n.ReadAt != nil {
+			readAt := n.ReadAt.Format(time.RFC3339)
+			item.ReadAt = &readAt
+		}
+		res = append(res, item)
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+	return res, types.PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   int(total),
+		ItemsPerPage: limit,
+	}, nil
+}
+
+func (s *wishlistService) MarkNotificationRead(userID, id string) error {
+	found, err := s.repo.MarkNotificationRead(userID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("not found")
+	}
+	return nil
+}
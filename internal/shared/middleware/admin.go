@@ -4,9 +4,12 @@ import (
 	"Start/internal/shared/utils"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"os"
 	"strings"
 )
 
+var requireAdminMFA = os.Getenv("REQUIRE_ADMIN_2FA") == "true"
+
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -31,6 +34,12 @@ func AdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if requireAdminMFA && !claims.MFA {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication enrollment required"})
+			c.Abort()
+			return
+		}
+
 		c.Set("userId", claims.UserID)
 		c.Next()
 	}
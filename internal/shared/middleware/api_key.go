@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(rawKey, clientIP, scope string) (string, error)
+}
+
+func APIKeyMiddleware(auth APIKeyAuthenticator, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader("X-API-Key")
+		if rawKey == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key missing"})
+			return
+		}
+
+		keyID, err := auth.AuthenticateAPIKey(rawKey, c.ClientIP(), scope)
+		if err != nil {
+			switch err.Error() {
+			case "invalid api key":
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or revoked API key"})
+			case "ip not allowed":
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Request IP not allowed for this API key"})
+			case "insufficient scope":
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key lacks the required scope"})
+			default:
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate API key"})
+			}
+			return
+		}
+
+		c.Set("apiKeyId", keyID)
+		c.Next()
+	}
+}
@@ -16,8 +16,8 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := utils.VerifyToken(tokenStr, utils.AccessTokenSecret)
-		if err != nil {
+		claims, err := utils.VerifyAccessToken(tokenStr)
+		if err != nil || utils.IsMFAChallengeClaims(claims) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired access token"})
 			return
 		}
@@ -0,0 +1,51 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"os"
+	"strings"
+)
+
+var ErrDeclined = errors.New("payment declined")
+
+type ChargeRequest struct {
+	// Reference identifies the charge on our side so providers can
+	// de-duplicate retries.
+	Reference string
+	UserID    string
+	AmountEGP float64
+	Method    string
+	Details   map[string]interface{}
+}
+
+// Provider charges users for purchases and subscription renewals. Charge
+// returns the provider's transaction ID, or ErrDeclined when the payment is
+// refused.
+type Provider interface {
+	Charge(ctx context.Context, req ChargeRequest) (string, error)
+}
+
+// NewProviderFromEnv selects the payment provider with PAYMENT_PROVIDER. Only
+// the built-in mock provider is available so far.
+func NewProviderFromEnv() Provider {
+	switch name := strings.ToLower(os.Getenv("PAYMENT_PROVIDER")); name {
+	case "", "mock":
+		return MockProvider{}
+	default:
+		log.Fatalf("Unknown payment provider %q", name)
+		return nil
+	}
+}
+
+// MockProvider approves every credit card charge and declines other methods.
+type MockProvider struct{}
+
+func (MockProvider) Charge(_ context.Context, req ChargeRequest) (string, error) {
+	if req.Method != "credit_card" {
+		return "", ErrDeclined
+	}
+	return "mock_" + uuid.NewString(), nil
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// BlobStore stores uploaded files under slash-separated keys and knows the
+// public URL each key is served from.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// NewBlobStoreFromEnv selects the backend with BLOB_STORE=local|s3. The local
+// store writes under BLOB_LOCAL_DIR and is served by the app itself.
+func NewBlobStoreFromEnv() BlobStore {
+	if strings.ToLower(os.Getenv("BLOB_STORE")) == "s3" {
+		s, err := NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			UseSSL:    os.Getenv("S3_USE_SSL") != "false",
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure S3 blob store: %v", err)
+		}
+		return s
+	}
+
+	dir := os.Getenv("BLOB_LOCAL_DIR")
+	if dir == "" {
+		dir = "./uploads"
+	}
+	publicURL := os.Getenv("BLOB_PUBLIC_URL")
+	if publicURL == "" {
+		publicURL = LocalPublicPath
+	}
+	return NewLocalStore(dir, publicURL)
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+)
+
+func EnvOrDefault(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+func EnvInt(key string, fallback int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return val
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const maxImagePixels = 40_000_000
+
+var MaxImageUploadBytes = int64(EnvInt("PRODUCT_IMAGE_MAX_BYTES", 5<<20))
+
+// ImageExtensions lists the accepted upload types, detected from the file
+// content rather than the client-supplied header.
+var ImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// DecodeImage sniffs and decodes an uploaded image, rejecting unsupported
+// types and images whose dimensions would take too much memory to decode.
+func DecodeImage(data []byte) (image.Image, string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := ImageExtensions[contentType]; !ok {
+		return nil, "", errors.New("unsupported image type")
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", errors.New("invalid image")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, "", errors.New("image dimensions too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", errors.New("invalid image")
+	}
+	return img, contentType, nil
+}
+
+// Thumbnail scales img to fit within size x size, keeping the aspect ratio,
+// and encodes it as PNG for PNG sources and JPEG otherwise.
+func Thumbnail(img image.Image, contentType string, size int) ([]byte, string, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if contentType == "image/png" {
+		if err := png.Encode(&buf, dst); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,29 +11,76 @@ import (
 
 var AccessTokenSecret = []byte(os.Getenv("ACCESS_SECRET"))
 var RefreshTokenSecret = []byte(os.Getenv("REFRESH_SECRET"))
+var MFAChallengeSecret = []byte(os.Getenv("MFA_CHALLENGE_SECRET"))
+var TokenIssuer = os.Getenv("JWT_ISSUER")
 
-func GenerateTokens(userID, email, role string) (string, string, error) {
-	accessToken, err := generateToken(userID, email, role, AccessTokenSecret, 15*time.Minute)
+// PreviousRefreshSecrets keeps refresh tokens signed before a secret rotation
+// valid until they expire.
+var PreviousRefreshSecrets = splitSecrets(os.Getenv("REFRESH_SECRET_PREVIOUS"))
+
+const mfaChallengePurpose = "mfa_challenge"
+
+func GenerateTokens(userID, email, role string, mfa bool) (string, string, error) {
+	accessToken, err := AccessKeys.Sign(userClaims(userID, email, role, mfa, "access", 15*time.Minute))
 	if err != nil {
 		return "", "", err
 	}
-	refreshToken, err := generateToken(userID, email, role, RefreshTokenSecret, 7*24*time.Hour)
+	refreshClaims := userClaims(userID, email, role, mfa, "refresh", 7*24*time.Hour)
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString(RefreshTokenSecret)
 	if err != nil {
 		return "", "", err
 	}
 	return accessToken, refreshToken, nil
 }
 
-func generateToken(userID, email, role string, secret []byte, duration time.Duration) (string, error) {
+func userClaims(userID, email, role string, mfa bool, tokenUse string, duration time.Duration) jwt.MapClaims {
+	now := time.Now()
 	claims := jwt.MapClaims{
-		"userId": userID,
-		"email":  email,
-		"role":   role,
-		"exp":    time.Now().Add(duration).Unix(),
+		"sub":       userID,
+		"userId":    userID,
+		"email":     email,
+		"role":      role,
+		"mfa":       mfa,
+		"token_use": tokenUse,
+		"iat":       now.Unix(),
+		"exp":       now.Add(duration).Unix(),
+	}
+	if TokenIssuer != "" {
+		claims["iss"] = TokenIssuer
 	}
+	return claims
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secret)
+func VerifyAccessToken(tokenString string) (jwt.MapClaims, error) {
+	claims, err := AccessKeys.Verify(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if use, _ := claims["token_use"].(string); use == "refresh" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func VerifyRefreshToken(tokenString string) (jwt.MapClaims, error) {
+	claims, err := VerifyToken(tokenString, RefreshTokenSecret)
+	for _, secret := range PreviousRefreshSecrets {
+		if err == nil {
+			break
+		}
+		claims, err = VerifyToken(tokenString, secret)
+	}
+	return claims, err
+}
+
+func splitSecrets(raw string) [][]byte {
+	var secrets [][]byte
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			secrets = append(secrets, []byte(s))
+		}
+	}
+	return secrets
 }
 
 func VerifyToken(tokenString string, secret []byte) (jwt.MapClaims, error) {
@@ -59,15 +107,17 @@ type UserClaims struct {
 	UserID string
 	Email  string
 	Role   string
+	MFA    bool
 }
 
 func ParseUserClaims(tokenStr string, isAccessToken bool) (*UserClaims, error) {
-	secret := AccessTokenSecret
-	if !isAccessToken {
-		secret = RefreshTokenSecret
+	var claimsMap jwt.MapClaims
+	var err error
+	if isAccessToken {
+		claimsMap, err = VerifyAccessToken(tokenStr)
+	} else {
+		claimsMap, err = VerifyRefreshToken(tokenStr)
 	}
-
-	claimsMap, err := VerifyToken(tokenStr, secret)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +125,7 @@ func ParseUserClaims(tokenStr string, isAccessToken bool) (*UserClaims, error) {
 	userID, _ := claimsMap["userId"].(string)
 	email, _ := claimsMap["email"].(string)
 	role, _ := claimsMap["role"].(string)
+	mfa, _ := claimsMap["mfa"].(bool)
 
 	if userID == "" || email == "" || role == "" {
 		return nil, errors.New("invalid claims data")
@@ -84,5 +135,40 @@ func ParseUserClaims(tokenStr string, isAccessToken bool) (*UserClaims, error) {
 		UserID: userID,
 		Email:  email,
 		Role:   role,
+		MFA:    mfa,
 	}, nil
 }
+
+// GenerateMFAChallengeToken binds the challenge to challengeID, which the
+// caller stores so the token can be used only once.
+func GenerateMFAChallengeToken(userID, challengeID string) (string, error) {
+	claims := jwt.MapClaims{
+		"userId":  userID,
+		"jti":     challengeID,
+		"purpose": mfaChallengePurpose,
+		"exp":     time.Now().Add(5 * time.Minute).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(MFAChallengeSecret)
+}
+
+func ParseMFAChallengeToken(tokenStr string) (string, string, error) {
+	claims, err := VerifyToken(tokenStr, MFAChallengeSecret)
+	if err != nil {
+		return "", "", err
+	}
+
+	purpose, _ := claims["purpose"].(string)
+	userID, _ := claims["userId"].(string)
+	challengeID, _ := claims["jti"].(string)
+	if purpose != mfaChallengePurpose || userID == "" || challengeID == "" {
+		return "", "", errors.New("invalid challenge token")
+	}
+	return userID, challengeID, nil
+}
+
+func IsMFAChallengeClaims(claims jwt.MapClaims) bool {
+	purpose, _ := claims["purpose"].(string)
+	return purpose == mfaChallengePurpose
+}
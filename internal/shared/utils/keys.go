@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// KeySet holds the key used to sign access tokens and every key that is still
+// accepted for verification, so a new key can be introduced before the old
+// one is retired without invalidating tokens already issued.
+type KeySet struct {
+	signer *signingKey
+	keys   map[string]*signingKey
+	hmac   []byte
+}
+
+var AccessKeys = loadAccessKeySet()
+
+func loadAccessKeySet() *KeySet {
+	alg := EnvOrDefault("JWT_SIGNING_ALG", "HS256")
+	dir := os.Getenv("JWT_KEYS_DIR")
+
+	if alg == "HS256" && dir == "" {
+		return &KeySet{keys: map[string]*signingKey{}, hmac: AccessTokenSecret}
+	}
+
+	ks, err := LoadKeySet(dir, os.Getenv("JWT_SIGNING_KEY_ID"), alg)
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+	return ks
+}
+
+// LoadKeySet reads <kid>.pem private keys and <kid>.pub.pem retired public
+// keys from dir. When dir is empty an ephemeral key is generated, which is
+// only suitable for local development.
+func LoadKeySet(dir, signingKID, alg string) (*KeySet, error) {
+	ks := &KeySet{keys: map[string]*signingKey{}}
+
+	if dir == "" {
+		key, err := generateSigningKey(alg)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("JWT_KEYS_DIR not set, using ephemeral %s key %s", alg, key.kid)
+		ks.keys[key.kid] = key
+		ks.signer = key
+		return ks, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		key, err := readKeyFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		ks.keys[key.kid] = key
+	}
+
+	signer, ok := ks.keys[signingKID]
+	if !ok || signer.private == nil {
+		return nil, fmt.Errorf("signing key %q not found in %s", signingKID, dir)
+	}
+	ks.signer = signer
+	return ks, nil
+}
+
+func (ks *KeySet) Sign(claims jwt.MapClaims) (string, error) {
+	if ks.signer == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.hmac)
+	}
+
+	token := jwt.NewWithClaims(ks.signer.method, claims)
+	token.Header["kid"] = ks.signer.kid
+	return token.SignedString(ks.signer.private)
+}
+
+func (ks *KeySet) Verify(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, ks.keyFunc)
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid claims")
+	}
+	return claims, nil
+}
+
+func (ks *KeySet) keyFunc(t *jwt.Token) (interface{}, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+		if ks.hmac == nil {
+			return nil, errors.New("unexpected signing method")
+		}
+		return ks.hmac, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown key id")
+	}
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.public, nil
+}
+
+func (ks *KeySet) JWKS() JWKSet {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, kid := range kids {
+		key := ks.keys[kid]
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.kid,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.kid,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return set
+}
+
+func readKeyFile(file string) (*signingKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	name := filepath.Base(file)
+	if strings.HasSuffix(name, ".pub.pem") {
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(strings.TrimSuffix(name, ".pub.pem"), nil, pub)
+	}
+
+	var priv interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	return newSigningKey(strings.TrimSuffix(name, ".pem"), signer, signer.Public())
+}
+
+func newSigningKey(kid string, private crypto.Signer, public crypto.PublicKey) (*signingKey, error) {
+	key := &signingKey{kid: kid, private: private, public: public}
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("unsupported key type, expected RSA or Ed25519")
+	}
+	return key, nil
+}
+
+func generateSigningKey(alg string) (*signingKey, error) {
+	kidBytes := make([]byte, 8)
+	if _, err := rand.Read(kidBytes); err != nil {
+		return nil, err
+	}
+	kid := base64.RawURLEncoding.EncodeToString(kidBytes)
+
+	switch alg {
+	case "RS256":
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(kid, priv, priv.Public())
+	case "EdDSA":
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(kid, priv, priv.Public())
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language of the base name and description columns;
+// other supported locales are stored as translations.
+var DefaultLocale = strings.ToLower(EnvOrDefault("DEFAULT_LOCALE", "en"))
+
+var SupportedLocales = map[string]bool{"en": true, "ar": true}
+
+// NegotiateLocale picks the locale from the lang query parameter, then from
+// the Accept-Language header by quality, falling back to DefaultLocale.
+func NegotiateLocale(lang, acceptLanguage string) string {
+	if l := baseLanguage(lang); SupportedLocales[l] {
+		return l
+	}
+
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		l := baseLanguage(fields[0])
+		if !SupportedLocales[l] {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{l, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	if len(candidates) > 0 {
+		return candidates[0].locale
+	}
+	return DefaultLocale
+}
+
+// baseLanguage reduces a language tag such as "ar-EG" to "ar".
+func baseLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
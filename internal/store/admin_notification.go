@@ -0,0 +1,12 @@
+package store
+
+import "time"
+
+type AdminNotification struct {
+	ID        string     `gorm:"primaryKey" json:"id"`
+	Kind      string     `gorm:"index" json:"kind"` // "low_stock"
+	ProductID *string    `gorm:"index" json:"product_id"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
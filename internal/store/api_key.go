@@ -0,0 +1,20 @@
+package store
+
+import (
+	"gorm.io/datatypes"
+	"time"
+)
+
+type APIKey struct {
+	ID         string         `gorm:"primaryKey" json:"id"`
+	Name       string         `json:"name"`
+	Prefix     string         `gorm:"uniqueIndex" json:"prefix"`
+	KeyHash    string         `json:"-"`
+	Scopes     datatypes.JSON `json:"scopes"`
+	AllowedIPs datatypes.JSON `json:"allowed_ips"`
+	CreatedBy  string         `json:"created_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	LastUsedIP string         `json:"last_used_ip"`
+	RevokedAt  *time.Time     `json:"revoked_at"`
+}
@@ -0,0 +1,27 @@
+package store
+
+import "time"
+
+type CartItem struct {
+	ID        string    `gorm:"primaryKey" json:"id"`
+	UserID    string    `gorm:"index" json:"user_id"`
+	ProductID string    `json:"product_id"`
+	VariantID *string   `json:"variant_id"`
+	Quantity  int       `json:"quantity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Product Product `gorm:"foreignKey:ProductID" json:"product"`
+}
+
+// RedemptionOrder groups the redemptions made by one cart checkout, which
+// were paid for with a single wallet change.
+type RedemptionOrder struct {
+	ID         string    `gorm:"primaryKey" json:"id"`
+	UserID     string    `gorm:"index" json:"user_id"`
+	PointsUsed int       `json:"points_used"`
+	ItemCount  int       `json:"item_count"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	Redemptions []Redemption `gorm:"foreignKey:OrderID" json:"redemptions"`
+}
@@ -1,9 +1,14 @@
 package store
 
+import "gorm.io/datatypes"
+
 type Category struct {
 	ID               string     `gorm:"primaryKey" json:"id"`
 	Name             string     `json:"name"`
 	Description      string     `json:"description"`
 	ParentCategoryID *string    `json:"parent_category_id"`
+	IsActive         bool       `gorm:"default:true" json:"is_active"`
 	Children         []Category `gorm:"foreignKey:ParentCategoryID" json:"children,omitempty"`
+
+	Translations datatypes.JSON `json:"translations"`
 }
@@ -0,0 +1,47 @@
+package store
+
+import "time"
+
+// BillableAction is an entry in the price list for spending credits, looked up
+// by its Key when products bill a user.
+type BillableAction struct {
+	ID             string    `gorm:"primaryKey" json:"id"`
+	Key            string    `gorm:"uniqueIndex" json:"key"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	CreditsPerUnit int       `json:"credits_per_unit"`
+	IsActive       bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CreditHold reserves credits for long-running work. The held credits leave
+// the wallet when the hold is placed; capturing returns what was not used and
+// releasing or expiring returns all of it.
+type CreditHold struct {
+	ID              string     `gorm:"primaryKey" json:"id"`
+	UserID          string     `gorm:"index" json:"user_id"`
+	ActionKey       string     `gorm:"index" json:"action_key"`
+	APIKeyID        *string    `gorm:"index" json:"api_key_id"`
+	Reference       string     `json:"reference"`
+	Quantity        int        `json:"quantity"`
+	UnitCredits     int        `json:"unit_credits"`
+	CreditsHeld     int        `json:"credits_held"`
+	CreditsCaptured int        `json:"credits_captured"`
+	Status          string     `gorm:"index" json:"status"` // "held", "captured", "released", "expired"
+	ExpiresAt       time.Time  `gorm:"index" json:"expires_at"`
+	SettledAt       *time.Time `json:"settled_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type CreditUsage struct {
+	ID        string    `gorm:"primaryKey" json:"id"`
+	UserID    string    `gorm:"index:idx_credit_usage_user_time" json:"user_id"`
+	ActionKey string    `gorm:"index" json:"action_key"`
+	Quantity  int       `json:"quantity"`
+	Credits   int       `json:"credits"`
+	HoldID    *string   `json:"hold_id"`
+	APIKeyID  *string   `gorm:"index" json:"api_key_id"`
+	Reference string    `json:"reference"`
+	CreatedAt time.Time `gorm:"index:idx_credit_usage_user_time" json:"created_at"`
+}
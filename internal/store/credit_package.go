@@ -1,6 +1,9 @@
 package store
 
-import "time"
+import (
+	"gorm.io/datatypes"
+	"time"
+)
 
 type CreditPackage struct {
 	ID           string    `json:"id"`
@@ -10,4 +13,6 @@ type CreditPackage struct {
 	RewardPoints int       `json:"reward_points"`
 	IsActive     bool      `json:"is_active"`
 	CreatedAt    time.Time `json:"created_at"`
+
+	Translations datatypes.JSON `json:"translations"`
 }
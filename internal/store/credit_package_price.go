@@ -0,0 +1,30 @@
+package store
+
+import "time"
+
+// CreditPackagePrice is one period of a credit package's pricing. The current
+// price is the row without an EffectiveTo.
+type CreditPackagePrice struct {
+	ID              string     `gorm:"primaryKey" json:"id"`
+	CreditPackageID string     `gorm:"index" json:"credit_package_id"`
+	PriceEGP        float64    `json:"price_egp"`
+	Credits         int        `json:"credits"`
+	RewardPoints    int        `json:"reward_points"`
+	EffectiveFrom   time.Time  `gorm:"index" json:"effective_from"`
+	EffectiveTo     *time.Time `json:"effective_to"`
+	ChangedBy       *string    `json:"changed_by"`
+	PriceChangeID   *string    `json:"price_change_id"`
+}
+
+type CreditPackagePriceChange struct {
+	ID              string     `gorm:"primaryKey" json:"id"`
+	CreditPackageID string     `gorm:"index" json:"credit_package_id"`
+	PriceEGP        float64    `json:"price_egp"`
+	Credits         int        `json:"credits"`
+	RewardPoints    int        `json:"reward_points"`
+	EffectiveAt     time.Time  `gorm:"index" json:"effective_at"`
+	Status          string     `gorm:"index;default:pending" json:"status"` // "pending", "applied", "cancelled", "superseded"
+	CreatedBy       *string    `json:"created_by"`
+	AppliedAt       *time.Time `json:"applied_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package store
+
+import "time"
+
+type InventoryMovement struct {
+	ID          string    `gorm:"primaryKey" json:"id"`
+	ProductID   string    `gorm:"index" json:"product_id"`
+	VariantID   *string   `gorm:"index" json:"variant_id"`
+	Delta       int       `json:"delta"`
+	StockAfter  int       `json:"stock_after"`
+	Reason      string    `json:"reason"` // "restock", "redemption", "cancellation_return", "rejection_return", "correction"
+	ReferenceID string    `json:"reference_id"`
+	Note        string    `json:"note"`
+	ActorUserID *string   `json:"actor_user_id"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
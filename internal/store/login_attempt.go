@@ -0,0 +1,11 @@
+package store
+
+import "time"
+
+type LoginAttempt struct {
+	Key           string    `gorm:"primaryKey" json:"key"` // "account:<email>" or "ip:<address>"
+	Failures      int       `json:"failures"`
+	LastFailureAt time.Time `json:"last_failure_at"` // start of the window for "ip:" keys
+
+	PreviousFailureAt *time.Time `json:"previous_failure_at"`
+}
@@ -0,0 +1,13 @@
+package store
+
+import "time"
+
+type Notification struct {
+	ID        string     `gorm:"primaryKey" json:"id"`
+	UserID    string     `gorm:"index" json:"user_id"`
+	Kind      string     `json:"kind"` // "back_in_stock", "affordable"
+	ProductID *string    `json:"product_id"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
@@ -6,15 +6,45 @@ import (
 )
 
 type Product struct {
-	ID               string         `gorm:"primaryKey" json:"id"`
-	Name             string         `json:"name"`
-	Description      string         `json:"description"`
-	CategoryID       string         `json:"category_id"`
-	Category         Category       `gorm:"foreignKey:CategoryID" json:"category"`
-	RedemptionPoints int            `json:"redemption_points"`
-	StockQuantity    int            `json:"stock_quantity"`
-	IsOffer          bool           `json:"is_offer"`
-	CreatedAt        time.Time      `json:"created_at"`
-	ImageURL         string         `json:"image_url"`
-	Tags             datatypes.JSON `json:"tags"`
+	ID               string           `gorm:"primaryKey" json:"id"`
+	ExternalSKU      *string          `gorm:"uniqueIndex" json:"external_sku"`
+	Name             string           `json:"name"`
+	Description      string           `json:"description"`
+	CategoryID       string           `json:"category_id"`
+	Category         Category         `gorm:"foreignKey:CategoryID" json:"category"`
+	RedemptionPoints int              `json:"redemption_points"`
+	StockQuantity    int              `json:"stock_quantity"`
+	IsOffer          bool             `json:"is_offer"`
+	IsActive         bool             `gorm:"default:true" json:"is_active"`
+	CreatedAt        time.Time        `json:"created_at"`
+	ImageURL         string           `json:"image_url"`
+	Tags             datatypes.JSON   `json:"tags"`
+	Variants         []ProductVariant `gorm:"foreignKey:ProductID" json:"variants,omitempty"`
+
+	OfferStartsAt         *time.Time `json:"offer_starts_at"`
+	OfferEndsAt           *time.Time `json:"offer_ends_at"`
+	OfferPoints           *int       `json:"offer_points"`
+	OfferQuantityCap      *int       `json:"offer_quantity_cap"`
+	OfferRedeemedQuantity int        `gorm:"not null;default:0" json:"offer_redeemed_quantity"`
+
+	LowStockThreshold *int `json:"low_stock_threshold"`
+
+	// RatingAverage and RatingCount cache the published reviews so listings
+	// can sort by rating.
+	RatingAverage float64 `gorm:"not null;default:0" json:"rating_average"`
+	RatingCount   int     `gorm:"not null;default:0" json:"rating_count"`
+
+	Translations datatypes.JSON `json:"translations"`
+
+	// With AllowCreditPayment set, up to MaxCreditShare percent of the points
+	// price can be paid with credits.
+	AllowCreditPayment bool `gorm:"not null;default:false" json:"allow_credit_payment"`
+	MaxCreditShare     int  `gorm:"not null;default:0" json:"max_credit_share"`
+
+	// Per-user redemption limits in units over rolling windows, and the wait
+	// between a user's redemptions of the product; nil means no limit.
+	MaxPerUserDaily    *int `json:"max_per_user_daily"`
+	MaxPerUserWeekly   *int `json:"max_per_user_weekly"`
+	MaxPerUserLifetime *int `json:"max_per_user_lifetime"`
+	CooldownMinutes    *int `json:"cooldown_minutes"`
 }
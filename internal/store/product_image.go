@@ -0,0 +1,17 @@
+package store
+
+import "time"
+
+type ProductImage struct {
+	ID           string    `gorm:"primaryKey" json:"id"`
+	ProductID    string    `gorm:"index" json:"product_id"`
+	Key          string    `json:"key"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	ContentType  string    `json:"content_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Position     int       `json:"position"`
+	IsPrimary    bool      `json:"is_primary"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package store
+
+import "time"
+
+type ProductReview struct {
+	ID             string    `gorm:"primaryKey" json:"id"`
+	ProductID      string    `gorm:"uniqueIndex:idx_product_review_author" json:"product_id"`
+	UserID         string    `gorm:"uniqueIndex:idx_product_review_author;index" json:"user_id"`
+	Rating         int       `json:"rating"`
+	Body           string    `json:"body"`
+	Status         string    `gorm:"index;default:published" json:"status"` // "published" or "hidden"
+	ModerationNote string    `json:"moderation_note"`
+	ModeratedBy    *string   `json:"moderated_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,17 @@
+package store
+
+import (
+	"gorm.io/datatypes"
+	"time"
+)
+
+type ProductVariant struct {
+	ID               string         `gorm:"primaryKey" json:"id"`
+	ProductID        string         `gorm:"index" json:"product_id"`
+	SKU              string         `gorm:"uniqueIndex" json:"sku"`
+	Options          datatypes.JSON `json:"options"`
+	StockQuantity    int            `json:"stock_quantity"`
+	RedemptionPoints *int           `json:"redemption_points"`
+	IsActive         bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
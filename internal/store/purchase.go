@@ -5,10 +5,30 @@ import "time"
 type Purchase struct {
 	ID              string    `json:"id" gorm:"primaryKey"`
 	UserID          string    `json:"user_id"`
-	CreditPackageID string    `json:"credit_package_id"`
+	CreditPackageID *string   `json:"credit_package_id"`
 	Status          string    `json:"status"`
 	Credits         int       `json:"credits"`
 	CreatedAt       time.Time `json:"created_at"`
 
+	// The package's pricing at the time of purchase, so later price changes
+	// do not rewrite past orders.
+	PriceEGP             float64 `gorm:"not null;default:0" json:"price_egp"`
+	RewardPoints         int     `gorm:"not null;default:0" json:"reward_points"`
+	CreditPackagePriceID *string `json:"credit_package_price_id"`
+
+	// Subscription renewals and plan changes have no credit package.
+	SubscriptionID       *string `gorm:"index" json:"subscription_id"`
+	PaymentTransactionID string  `json:"payment_transaction_id"`
+
+	// Subscription charges are saved as pending before the payment is taken
+	// and settled afterwards. PaymentReference goes with every attempt so the
+	// provider can de-duplicate a retry; settling a paid charge moves the
+	// subscription to SubscriptionPlanID and, with PeriodStart, starts that
+	// period. A declined charge returns BalanceAppliedEGP to the balance.
+	PaymentReference   string     `json:"payment_reference"`
+	SubscriptionPlanID *string    `json:"subscription_plan_id"`
+	PeriodStart        *time.Time `json:"period_start"`
+	BalanceAppliedEGP  float64    `gorm:"not null;default:0" json:"balance_applied_egp"`
+
 	CreditPackage CreditPackage `gorm:"foreignKey:CreditPackageID"`
 }
@@ -0,0 +1,11 @@
+package store
+
+import "time"
+
+type RecoveryCode struct {
+	ID        string     `gorm:"primaryKey" json:"id"`
+	UserID    string     `gorm:"index" json:"user_id"`
+	CodeHash  string     `gorm:"uniqueIndex" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
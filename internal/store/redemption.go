@@ -3,12 +3,29 @@ package store
 import "time"
 
 type Redemption struct {
-	ID        string    `gorm:"primaryKey" json:"id"`
-	UserID    string    `json:"user_id"`
-	ProductID string    `json:"product_id"`
-	Status    string    `json:"status"`
-	Quantity  int       `json:"quantity"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         string    `gorm:"primaryKey" json:"id"`
+	UserID     string    `json:"user_id"`
+	ProductID  string    `json:"product_id"`
+	VariantID  *string   `gorm:"index" json:"variant_id"`
+	Status     string    `json:"status"`
+	Quantity   int       `json:"quantity"`
+	PointsUsed int       `json:"points_used"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// CreditsUsed paid for part of the price at PointsPerCredit points each;
+	// PointsUsed is what was taken from the points balance.
+	CreditsUsed     int `gorm:"not null;default:0" json:"credits_used"`
+	PointsPerCredit int `gorm:"not null;default:0" json:"points_per_credit"`
+
+	OrderID *string `gorm:"index" json:"order_id"`
+
+	// AssignedTo is the admin handling the redemption; StatusReason explains
+	// the last status change, such as why it was rejected.
+	AssignedTo      *string    `gorm:"index" json:"assigned_to"`
+	AssignedAt      *time.Time `json:"assigned_at"`
+	StatusReason    string     `json:"status_reason"`
+	StatusChangedBy *string    `json:"status_changed_by"`
+	StatusChangedAt *time.Time `json:"status_changed_at"`
 
 	Product Product `gorm:"foreignKey:ProductID" json:"product"`
 }
@@ -0,0 +1,11 @@
+package store
+
+import "time"
+
+// Setting holds a value that admins can change at runtime, keyed by name.
+type Setting struct {
+	Key       string    `gorm:"primaryKey" json:"key"`
+	Value     string    `json:"value"`
+	UpdatedBy *string   `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package store
+
+import "time"
+
+type SubscriptionPlan struct {
+	ID           string    `gorm:"primaryKey" json:"id"`
+	Name         string    `json:"name"`
+	PriceEGP     float64   `json:"price_egp"` // per monthly billing cycle
+	Credits      int       `json:"credits"`
+	RewardPoints int       `json:"reward_points"`
+	TrialDays    int       `gorm:"not null;default:0" json:"trial_days"`
+	IsActive     bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Subscription bills its plan every month. RenewsAt is when the scheduler
+// next charges it: the end of the current period, or the next retry while
+// the subscription is past due. A user has at most one subscription that is
+// not cancelled; one whose first charge has not settled yet is incomplete.
+type Subscription struct {
+	ID                 string     `gorm:"primaryKey" json:"id"`
+	UserID             string     `gorm:"uniqueIndex:idx_subscription_open_user,where:status <> 'cancelled'" json:"user_id"`
+	PlanID             string     `gorm:"index" json:"plan_id"`
+	Status             string     `gorm:"index" json:"status"` // "incomplete", "trialing", "active", "past_due", "paused", "cancelled"
+	PaymentMethod      string     `json:"payment_method"`
+	CurrentPeriodStart time.Time  `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time  `json:"current_period_end"`
+	RenewsAt           time.Time  `gorm:"index" json:"renews_at"`
+	CancelAtPeriodEnd  bool       `json:"cancel_at_period_end"`
+	BalanceEGP         float64    `gorm:"not null;default:0" json:"balance_egp"` // proration credit applied to the next charge
+	FailedAttempts     int        `gorm:"not null;default:0" json:"failed_attempts"`
+	PausedAt           *time.Time `json:"paused_at"`
+	CancelledAt        *time.Time `json:"cancelled_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	Plan SubscriptionPlan `gorm:"foreignKey:PlanID" json:"plan"`
+}
@@ -13,5 +13,13 @@ type User struct {
 	Status       string    `json:"status"` // "suspended" or "active", "banned"
 	CreatedAt    time.Time `json:"created_at"`
 
+	TOTPSecret       string `json:"-"`
+	TOTPEnabled      bool   `json:"totp_enabled"`
+	TOTPLastUsedStep int64  `json:"-"`
+
+	// MFAChallengeID identifies the one outstanding MFA challenge; it is
+	// cleared when the challenge is completed.
+	MFAChallengeID string `json:"-"`
+
 	Wallet Wallet `gorm:"foreignKey:UserID"`
 }
@@ -0,0 +1,20 @@
+package store
+
+import "time"
+
+type UserIdentity struct {
+	ID        string    `gorm:"primaryKey" json:"id"`
+	UserID    string    `gorm:"index" json:"user_id"`
+	Provider  string    `gorm:"uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject   string    `gorm:"uniqueIndex:idx_identity_provider_subject" json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OIDCLoginState struct {
+	State        string    `gorm:"primaryKey" json:"state"`
+	Provider     string    `json:"provider"`
+	Nonce        string    `json:"-"`
+	CodeVerifier string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
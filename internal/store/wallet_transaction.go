@@ -0,0 +1,16 @@
+package store
+
+import "time"
+
+type WalletTransaction struct {
+	ID           string    `gorm:"primaryKey" json:"id"`
+	UserID       string    `gorm:"index" json:"user_id"`
+	PointsDelta  int       `json:"points_delta"`
+	CreditsDelta int       `json:"credits_delta"`
+	Reason       string    `json:"reason"` // "purchase", "redemption", "admin_adjustment", "integration_award", "subscription", "consumption", "consumption_hold", "consumption_release", "redemption_refund"
+	ReferenceID  string    `json:"reference_id"`
+	Note         string    `json:"note"`
+	ActorUserID  *string   `json:"actor_user_id"`
+	APIKeyID     *string   `gorm:"index" json:"api_key_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
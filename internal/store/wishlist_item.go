@@ -0,0 +1,15 @@
+package store
+
+import "time"
+
+type WishlistItem struct {
+	ID        string `gorm:"primaryKey" json:"id"`
+	UserID    string `gorm:"uniqueIndex:idx_wishlist_item_user_product" json:"user_id"`
+	ProductID string `gorm:"uniqueIndex:idx_wishlist_item_user_product;index" json:"product_id"`
+	// AffordableNotifiedAt is set once the user has been told they can afford
+	// the product, so the notification is only sent the first time.
+	AffordableNotifiedAt *time.Time `json:"affordable_notified_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+
+	Product Product `gorm:"foreignKey:ProductID" json:"product"`
+}
@@ -0,0 +1,38 @@
+package types
+
+type CreateAPIKeyRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	Scopes     []string `json:"scopes" binding:"required,min=1"`
+	AllowedIPs []string `json:"allowedIps"`
+}
+
+type APIKeyResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	AllowedIPs []string `json:"allowedIps"`
+	CreatedBy  string   `json:"createdBy"`
+	CreatedAt  string   `json:"createdAt"`
+	LastUsedAt *string  `json:"lastUsedAt,omitempty"`
+	LastUsedIP string   `json:"lastUsedIp,omitempty"`
+	RevokedAt  *string  `json:"revokedAt,omitempty"`
+}
+
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type AwardPointsRequest struct {
+	Points    int    `json:"points" binding:"required,gt=0"`
+	Reason    string `json:"reason"`
+	Reference string `json:"reference"`
+}
+
+type IntegrationWalletResponse struct {
+	UserID         string `json:"userId"`
+	PointsBalance  int    `json:"pointsBalance"`
+	CreditsBalance int    `json:"creditsBalance"`
+	UpdatedAt      string `json:"updatedAt"`
+}
@@ -15,9 +15,17 @@ type LoginInput struct {
 }
 
 type LoginResponse struct {
-	AccessToken  string  `json:"accessToken"`
-	RefreshToken string  `json:"refreshToken"`
-	User         UserDTO `json:"user"`
+	AccessToken    string  `json:"accessToken"`
+	RefreshToken   string  `json:"refreshToken"`
+	User           UserDTO `json:"user"`
+	MFARequired    bool    `json:"mfaRequired"`
+	ChallengeToken string  `json:"challengeToken,omitempty"`
+}
+
+type MFALoginInput struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recoveryCode"`
 }
 
 type RefreshRequest struct {
@@ -34,3 +42,22 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"newPassword" binding:"required,min=8"`
 	ConfirmPassword string `json:"confirmPassword" binding:"required,eqfield=NewPassword"`
 }
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type TOTPActivationResponse struct {
+	RecoveryCodes []string  `json:"recoveryCodes"`
+	Tokens        TokenPair `json:"tokens"`
+}
@@ -0,0 +1,39 @@
+package types
+
+type AddCartItemRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	VariantID string `json:"variant_id"`
+	Quantity  int    `json:"quantity" binding:"required,gt=0"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,gt=0"`
+}
+
+// CartItemResponse prices a line at the live points and stock. A line that
+// cannot be redeemed as it stands carries the reason in Problem.
+type CartItemResponse struct {
+	ID             string            `json:"id"`
+	Product        RedemptionProduct `json:"product"`
+	VariantID      *string           `json:"variant_id,omitempty"`
+	Quantity       int               `json:"quantity"`
+	UnitPoints     int               `json:"unit_points"`
+	LinePoints     int               `json:"line_points"`
+	AvailableStock int               `json:"available_stock"`
+	Problem        string            `json:"problem,omitempty"`
+}
+
+type CartResponse struct {
+	Items         []CartItemResponse `json:"items"`
+	TotalPoints   int                `json:"total_points"`
+	PointsBalance int                `json:"points_balance"`
+	CanCheckout   bool               `json:"can_checkout"`
+}
+
+type RedemptionOrderResponse struct {
+	ID         string                `json:"id"`
+	PointsUsed int                   `json:"points_used"`
+	ItemCount  int                   `json:"item_count"`
+	Items      []*RedemptionResponse `json:"items"`
+	CreatedAt  string                `json:"created_at"`
+}
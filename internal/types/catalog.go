@@ -0,0 +1,34 @@
+package types
+
+type CatalogProductRow struct {
+	ExternalSKU      string   `json:"externalSku"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Category         string   `json:"category"`
+	RedemptionPoints int      `json:"redemptionPoints"`
+	StockQuantity    int      `json:"stockQuantity"`
+	IsOffer          bool     `json:"isOffer"`
+	IsActive         *bool    `json:"isActive,omitempty"`
+	Tags             []string `json:"tags"`
+	ImageURL         string   `json:"imageUrl,omitempty"`
+}
+
+type CatalogCategoryRow struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Parent      string `json:"parent,omitempty"`
+}
+
+type ImportRowError struct {
+	Row    int      `json:"row"`
+	Key    string   `json:"key,omitempty"`
+	Errors []string `json:"errors"`
+}
+
+type ImportResult struct {
+	DryRun  bool             `json:"dryRun"`
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Errors  []ImportRowError `json:"errors"`
+}
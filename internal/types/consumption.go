@@ -0,0 +1,88 @@
+package types
+
+type CreateBillableActionRequest struct {
+	Key            string `json:"key" binding:"required"`
+	Name           string `json:"name" binding:"required"`
+	Description    string `json:"description"`
+	CreditsPerUnit int    `json:"creditsPerUnit" binding:"required,gt=0"`
+	IsActive       *bool  `json:"isActive"`
+}
+
+type UpdateBillableActionRequest struct {
+	Name           *string `json:"name"`
+	Description    *string `json:"description"`
+	CreditsPerUnit *int    `json:"creditsPerUnit"`
+	IsActive       *bool   `json:"isActive"`
+}
+
+type BillableActionResponse struct {
+	ID             string `json:"id"`
+	Key            string `json:"key"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	CreditsPerUnit int    `json:"creditsPerUnit"`
+	IsActive       bool   `json:"isActive"`
+	UpdatedAt      string `json:"updatedAt"`
+}
+
+type ConsumeCreditsRequest struct {
+	Action    string `json:"action" binding:"required"`
+	Quantity  int    `json:"quantity"`
+	Reference string `json:"reference"`
+}
+
+type CreditHoldRequest struct {
+	Action     string `json:"action" binding:"required"`
+	Quantity   int    `json:"quantity"`
+	Reference  string `json:"reference"`
+	TTLSeconds int    `json:"ttlSeconds"`
+}
+
+type CaptureHoldRequest struct {
+	Quantity *int `json:"quantity"`
+}
+
+type CreditUsageResponse struct {
+	ID             string  `json:"id"`
+	UserID         string  `json:"userId"`
+	Action         string  `json:"action"`
+	Quantity       int     `json:"quantity"`
+	Credits        int     `json:"credits"`
+	HoldID         *string `json:"holdId,omitempty"`
+	Reference      string  `json:"reference,omitempty"`
+	CreditsBalance int     `json:"creditsBalance"`
+	CreatedAt      string  `json:"createdAt"`
+}
+
+type CreditHoldResponse struct {
+	ID              string  `json:"id"`
+	UserID          string  `json:"userId"`
+	Action          string  `json:"action"`
+	Quantity        int     `json:"quantity"`
+	UnitCredits     int     `json:"unitCredits"`
+	CreditsHeld     int     `json:"creditsHeld"`
+	CreditsCaptured int     `json:"creditsCaptured"`
+	Status          string  `json:"status"`
+	Reference       string  `json:"reference,omitempty"`
+	CreditsBalance  int     `json:"creditsBalance"`
+	ExpiresAt       string  `json:"expiresAt"`
+	SettledAt       *string `json:"settledAt,omitempty"`
+	CreatedAt       string  `json:"createdAt"`
+}
+
+type UsageReportItem struct {
+	Action     string `json:"action"`
+	Name       string `json:"name"`
+	Events     int64  `json:"events"`
+	Quantity   int64  `json:"quantity"`
+	Credits    int64  `json:"credits"`
+	LastUsedAt string `json:"lastUsedAt"`
+}
+
+type UsageReport struct {
+	UserID       string            `json:"userId"`
+	DateFrom     string            `json:"dateFrom,omitempty"`
+	DateTo       string            `json:"dateTo,omitempty"`
+	TotalCredits int64             `json:"totalCredits"`
+	Actions      []UsageReportItem `json:"actions"`
+}
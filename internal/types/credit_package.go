@@ -1,11 +1,15 @@
 package types
 
+import "time"
+
 type CreateCreditPackageRequest struct {
 	Name         string  `json:"name" binding:"required"`
 	PriceEGP     float64 `json:"priceEgp" binding:"required"`
 	Credits      int     `json:"credits" binding:"required"`
 	RewardPoints int     `json:"rewardPoints" binding:"required"`
 	IsActive     bool    `json:"isActive" binding:"required"`
+
+	Translations Translations `json:"translations"`
 }
 
 type UpdateCreditPackageRequest CreateCreditPackageRequest
@@ -18,9 +22,42 @@ type CreditCreditPackageResponse struct {
 	RewardPoints int     `json:"rewardPoints"`
 	IsActive     bool    `json:"isActive"`
 	CreatedAt    string  `json:"createdAt"`
+
+	Translations Translations `json:"translations,omitempty"`
 }
 
 type PaginatedResponse struct {
 	Packages   []CreditCreditPackageResponse `json:"packages"`
 	Pagination PaginationMeta                `json:"pagination"`
 }
+
+type ScheduledPriceChangeRequest struct {
+	PriceEGP     float64   `json:"priceEgp" binding:"required"`
+	Credits      int       `json:"credits" binding:"required"`
+	RewardPoints int       `json:"rewardPoints"`
+	EffectiveAt  time.Time `json:"effectiveAt" binding:"required"`
+}
+
+type PriceChangeResponse struct {
+	ID              string  `json:"id"`
+	CreditPackageID string  `json:"creditPackageId"`
+	PriceEGP        float64 `json:"priceEgp"`
+	Credits         int     `json:"credits"`
+	RewardPoints    int     `json:"rewardPoints"`
+	EffectiveAt     string  `json:"effectiveAt"`
+	Status          string  `json:"status"`
+	CreatedBy       *string `json:"createdBy,omitempty"`
+	AppliedAt       *string `json:"appliedAt,omitempty"`
+	CreatedAt       string  `json:"createdAt"`
+}
+
+type PriceHistoryEntry struct {
+	ID            string  `json:"id"`
+	PriceEGP      float64 `json:"priceEgp"`
+	Credits       int     `json:"credits"`
+	RewardPoints  int     `json:"rewardPoints"`
+	EffectiveFrom string  `json:"effectiveFrom"`
+	EffectiveTo   *string `json:"effectiveTo,omitempty"`
+	ChangedBy     *string `json:"changedBy,omitempty"`
+	PriceChangeID *string `json:"priceChangeId,omitempty"`
+}
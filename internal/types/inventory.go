@@ -0,0 +1,41 @@
+package types
+
+type StockAdjustmentRequest struct {
+	Delta     int    `json:"delta"`
+	Reason    string `json:"reason"`
+	Note      string `json:"note"`
+	VariantID string `json:"variantId"`
+}
+
+type InventoryMovementResponse struct {
+	ID          string  `json:"id"`
+	ProductID   string  `json:"productId"`
+	VariantID   *string `json:"variantId,omitempty"`
+	Delta       int     `json:"delta"`
+	StockAfter  int     `json:"stockAfter"`
+	Reason      string  `json:"reason"`
+	ReferenceID string  `json:"referenceId,omitempty"`
+	Note        string  `json:"note,omitempty"`
+	ActorUserID *string `json:"actorUserId,omitempty"`
+	CreatedAt   string  `json:"createdAt"`
+}
+
+type AdminNotificationResponse struct {
+	ID        string  `json:"id"`
+	Kind      string  `json:"kind"`
+	ProductID *string `json:"productId,omitempty"`
+	Message   string  `json:"message"`
+	ReadAt    *string `json:"readAt"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+type StockForecastItem struct {
+	ProductID           string  `json:"productId"`
+	Name                string  `json:"name"`
+	StockQuantity       int     `json:"stockQuantity"`
+	LowStockThreshold   *int    `json:"lowStockThreshold,omitempty"`
+	RedeemedInWindow    int     `json:"redeemedInWindow"`
+	DailyRate           float64 `json:"dailyRate"`
+	DaysRemaining       float64 `json:"daysRemaining"`
+	ProjectedStockoutAt string  `json:"projectedStockoutAt"`
+}
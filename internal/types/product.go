@@ -1,25 +1,118 @@
 package types
 
-import "Start/internal/store"
+import (
+	"Start/internal/store"
+	"time"
+)
 
 type ProductFilters struct {
-	CategoryID string
-	IsOffer    *bool
-	MinPoints  int
-	MaxPoints  int
+	CategoryIDs []string
+	Tags        []string
+	IsOffer     *bool
+	InStock     *bool
+	MinPoints   int
+	MaxPoints   int
+	Archived    bool
+
+	IncludeDescendants bool // CategoryIDs also match their subcategories
 }
 
 type ProductResponse struct {
-	ID               string           `json:"id"`
-	Name             string           `json:"name"`
-	Description      string           `json:"description"`
-	Category         *CategorySummary `json:"category,omitempty"`
-	RedemptionPoints int              `json:"redemptionPoints"`
-	StockQuantity    int              `json:"stockQuantity"`
-	IsOffer          bool             `json:"isOffer"`
-	ImageURL         *string          `json:"imageUrl,omitempty"`
-	Tags             []string         `json:"tags"`
-	CreatedAt        string           `json:"createdAt"`
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Description      string            `json:"description"`
+	Category         *CategorySummary  `json:"category,omitempty"`
+	RedemptionPoints int               `json:"redemptionPoints"`
+	StockQuantity    int               `json:"stockQuantity"`
+	IsOffer          bool              `json:"isOffer"`
+	IsActive         bool              `json:"isActive"`
+	ImageURL         *string           `json:"imageUrl,omitempty"`
+	Tags             []string          `json:"tags"`
+	CreatedAt        string            `json:"createdAt"`
+	Offer            *OfferResponse    `json:"offer,omitempty"`
+	Variants         []VariantResponse `json:"variants,omitempty"`
+
+	LowStockThreshold *int          `json:"lowStockThreshold,omitempty"`
+	Rating            RatingSummary `json:"rating"`
+
+	Translations Translations `json:"translations,omitempty"`
+
+	CreditPayment    *CreditPaymentRule `json:"creditPayment,omitempty"`
+	RedemptionLimits *RedemptionLimits  `json:"redemptionLimits,omitempty"`
+}
+
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+type VariantResponse struct {
+	ID               string            `json:"id"`
+	SKU              string            `json:"sku"`
+	Options          map[string]string `json:"options"`
+	StockQuantity    int               `json:"stockQuantity"`
+	RedemptionPoints int               `json:"redemptionPoints"`
+}
+
+type CreateVariantRequest struct {
+	SKU              string            `json:"sku"`
+	Options          map[string]string `json:"options"`
+	StockQuantity    int               `json:"stockQuantity"`
+	RedemptionPoints *int              `json:"redemptionPoints"`
+}
+
+type UpdateVariantRequest struct {
+	SKU              *string           `json:"sku"`
+	Options          map[string]string `json:"options"`
+	StockQuantity    *int              `json:"stockQuantity"`
+	RedemptionPoints *int              `json:"redemptionPoints"`
+}
+
+type OfferResponse struct {
+	Active       bool    `json:"active"`
+	Points       int     `json:"points"`
+	StartsAt     *string `json:"startsAt,omitempty"`
+	EndsAt       *string `json:"endsAt,omitempty"`
+	QuantityCap  *int    `json:"quantityCap,omitempty"`
+	RemainingCap *int    `json:"remainingCap,omitempty"`
+}
+
+type ProductSearchHit struct {
+	*ProductResponse
+	Score              float64 `json:"score"`
+	NameHighlight      string  `json:"nameHighlight"`
+	DescriptionSnippet string  `json:"descriptionSnippet"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type CategoryFacet struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	ParentCategoryID *string `json:"parentCategoryId,omitempty"`
+	Count            int64   `json:"count"`
+	DirectCount      int64   `json:"directCount"`
+}
+
+type PointsRange struct {
+	Min int `json:"min"`
+	Max int `json:"max,omitempty"`
+}
+
+type PointsFacet struct {
+	PointsRange
+	Count int64 `json:"count"`
+}
+
+type ProductFacets struct {
+	Categories []CategoryFacet `json:"categories"`
+	Tags       []FacetCount    `json:"tags"`
+	Points     []PointsFacet   `json:"points"`
+	Offer      []FacetCount    `json:"offer"`
+	Stock      []FacetCount    `json:"stock"`
 }
 
 type CategorySimple struct {
@@ -35,22 +128,29 @@ type PaginationMeta struct {
 }
 
 type CreateCategoryRequest struct {
-	Name             string `json:"name"`
-	Description      string `json:"description"`
-	ParentCategoryID string `json:"parentCategoryId"`
+	Name             string       `json:"name"`
+	Description      string       `json:"description"`
+	ParentCategoryID string       `json:"parentCategoryId"`
+	Translations     Translations `json:"translations"`
 }
 
 type UpdateCategoryRequest struct {
-	Name             *string `json:"name"`
-	Description      *string `json:"description"`
-	ParentCategoryID *string `json:"parentCategoryId"`
+	Name             *string      `json:"name"`
+	Description      *string      `json:"description"`
+	ParentCategoryID *string      `json:"parentCategoryId"`
+	Translations     Translations `json:"translations"`
+}
+
+type MoveCategoryRequest struct {
+	ParentCategoryID string `json:"parentCategoryId"`
 }
 
 type CategoryResponse struct {
-	ID               string  `json:"id"`
-	Name             string  `json:"name"`
-	Description      string  `json:"description"`
-	ParentCategoryID *string `json:"parentCategoryId,omitempty"`
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	Description      string       `json:"description"`
+	ParentCategoryID *string      `json:"parentCategoryId,omitempty"`
+	Translations     Translations `json:"translations,omitempty"`
 }
 
 type CreateProductRequest struct {
@@ -62,6 +162,36 @@ type CreateProductRequest struct {
 	IsOffer          bool     `json:"isOffer"`
 	ImageURL         *string  `json:"imageUrl,omitempty"`
 	Tags             []string `json:"tags"`
+	OfferSchedule
+	LowStockThreshold *int         `json:"lowStockThreshold"`
+	Translations      Translations `json:"translations"`
+
+	CreditPayment *CreditPaymentRule `json:"creditPayment"`
+
+	RedemptionLimits *RedemptionLimits `json:"redemptionLimits"`
+}
+
+// RedemptionLimits caps how many units one user may redeem per rolling day,
+// week or ever, and how long they wait between redemptions.
+type RedemptionLimits struct {
+	MaxPerUserDaily    *int `json:"maxPerUserDaily"`
+	MaxPerUserWeekly   *int `json:"maxPerUserWeekly"`
+	MaxPerUserLifetime *int `json:"maxPerUserLifetime"`
+	CooldownMinutes    *int `json:"cooldownMinutes"`
+}
+
+// CreditPaymentRule lets users pay up to MaxSharePercent of a product's
+// points price with credits.
+type CreditPaymentRule struct {
+	Allowed         bool `json:"allowed"`
+	MaxSharePercent int  `json:"maxSharePercent"`
+}
+
+type OfferSchedule struct {
+	OfferStartsAt    *time.Time `json:"offerStartsAt"`
+	OfferEndsAt      *time.Time `json:"offerEndsAt"`
+	OfferPoints      *int       `json:"offerPoints"`
+	OfferQuantityCap *int       `json:"offerQuantityCap"`
 }
 
 type UpdateProductRequest struct {
@@ -73,6 +203,29 @@ type UpdateProductRequest struct {
 	IsOffer          *bool    `json:"isOffer"`
 	ImageURL         *string  `json:"imageUrl,omitempty"`
 	Tags             []string `json:"tags"`
+	OfferSchedule
+	ClearOfferSchedule     bool `json:"clearOfferSchedule"`
+	LowStockThreshold      *int `json:"lowStockThreshold"`
+	ClearLowStockThreshold bool `json:"clearLowStockThreshold"`
+
+	// Translations are merged per locale; a locale with an empty name and
+	// description is removed.
+	Translations Translations `json:"translations"`
+
+	CreditPayment *CreditPaymentRule `json:"creditPayment"`
+
+	// RedemptionLimits replaces all limits; omitted fields become unlimited.
+	RedemptionLimits *RedemptionLimits `json:"redemptionLimits"`
+}
+
+type CategoryTreeNode struct {
+	ID                 string              `json:"id"`
+	Name               string              `json:"name"`
+	Description        string              `json:"description"`
+	ParentCategoryID   *string             `json:"parentCategoryId,omitempty"`
+	ProductCount       int64               `json:"productCount"`
+	DirectProductCount int64               `json:"directProductCount"`
+	Children           []*CategoryTreeNode `json:"children"`
 }
 
 type CategorySummary struct {
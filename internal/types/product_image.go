@@ -0,0 +1,23 @@
+package types
+
+type ImageUpload struct {
+	Filename string
+	Data     []byte
+}
+
+type ProductImageResponse struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	ContentType  string `json:"contentType"`
+	SizeBytes    int64  `json:"sizeBytes"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Position     int    `json:"position"`
+	IsPrimary    bool   `json:"isPrimary"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+type ReorderProductImagesRequest struct {
+	ImageIDs []string `json:"imageIds"`
+}
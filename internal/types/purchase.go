@@ -9,11 +9,14 @@ type CreatePurchaseRequest struct {
 type PurchaseResponse struct {
 	ID                string             `json:"id"`
 	UserID            string             `json:"userId"`
-	CreditPackageID   string             `json:"creditPackageId"`
+	CreditPackageID   *string            `json:"creditPackageId,omitempty"`
 	Status            string             `json:"status"`
 	Credits           int                `json:"credits"`
+	RewardPoints      int                `json:"rewardPoints"`
 	CreatedAt         string             `json:"createdAt"`
 	CreditPackageInfo *SimplePackageInfo `json:"creditPackage,omitempty"`
+	SubscriptionID    *string            `json:"subscriptionId,omitempty"`
+	PriceEGP          float64            `json:"priceEgp"`
 }
 
 type SimplePackageInfo struct {
@@ -2,15 +2,41 @@ package types
 
 type CreateRedemptionRequest struct {
 	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id"`
 	Quantity  int    `json:"quantity"`
+
+	// CreditsUsed pays for part of the points price with credits; the rest
+	// comes from the points balance.
+	CreditsUsed int `json:"credits_used"`
 }
 
 type RedemptionResponse struct {
 	ID         string            `json:"id"`
 	Product    RedemptionProduct `json:"product"`
+	VariantID  *string           `json:"variant_id,omitempty"`
 	Quantity   int               `json:"quantity"`
 	PointsUsed int               `json:"points_used"`
 	CreatedAt  string            `json:"created_at"`
+
+	CreditsUsed     int     `json:"credits_used"`
+	PointsPerCredit int     `json:"points_per_credit,omitempty"`
+	OrderID         *string `json:"order_id,omitempty"`
+
+	Allowance *RedemptionAllowance `json:"allowance,omitempty"`
+}
+
+// RedemptionAllowance is what the user may still redeem of a product; a
+// missing field means that limit is not set.
+type RedemptionAllowance struct {
+	DailyRemaining    *int    `json:"daily_remaining,omitempty"`
+	WeeklyRemaining   *int    `json:"weekly_remaining,omitempty"`
+	LifetimeRemaining *int    `json:"lifetime_remaining,omitempty"`
+	OfferRemaining    *int    `json:"offer_remaining,omitempty"`
+	NextRedemptionAt  *string `json:"next_redemption_at,omitempty"`
+}
+
+type RedemptionPaymentSettings struct {
+	PointsPerCredit int `json:"points_per_credit"`
 }
 
 type RedemptionProduct struct {
@@ -18,3 +44,48 @@ type RedemptionProduct struct {
 	Name         string `json:"name"`
 	RewardPoints int    `json:"reward_points"`
 }
+
+type RedemptionQueueFilters struct {
+	Status      string
+	ProductID   string
+	CategoryID  string
+	AssignedTo  string
+	MinAgeHours int
+	MaxAgeHours int
+	Overdue     bool
+}
+
+// RedemptionQueueItem is a redemption as fulfilment staff see it, with the
+// SLA clock measured from when it was made.
+type RedemptionQueueItem struct {
+	RedemptionResponse
+	UserID       string           `json:"user_id"`
+	Status       string           `json:"status"`
+	Category     *CategorySummary `json:"category,omitempty"`
+	AssignedTo   *string          `json:"assigned_to,omitempty"`
+	AssignedAt   *string          `json:"assigned_at,omitempty"`
+	StatusReason string           `json:"status_reason,omitempty"`
+	AgeMinutes   int              `json:"age_minutes"`
+	DueAt        string           `json:"due_at"`
+	Overdue      bool             `json:"overdue"`
+}
+
+type BulkRedemptionItem struct {
+	ID     string `json:"id" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+type BulkRedemptionRequest struct {
+	Action string               `json:"action" binding:"required"`
+	Items  []BulkRedemptionItem `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+type BulkRedemptionResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type AssignRedemptionRequest struct {
+	AdminID string `json:"admin_id"`
+}
@@ -0,0 +1,24 @@
+package types
+
+type ReviewRequest struct {
+	Rating int    `json:"rating"`
+	Body   string `json:"body"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+type ReviewResponse struct {
+	ID             string `json:"id"`
+	ProductID      string `json:"productId"`
+	AuthorID       string `json:"authorId"`
+	AuthorName     string `json:"authorName"`
+	Rating         int    `json:"rating"`
+	Body           string `json:"body"`
+	Status         string `json:"status"`
+	ModerationNote string `json:"moderationNote,omitempty"`
+	CreatedAt      string `json:"createdAt"`
+	UpdatedAt      string `json:"updatedAt"`
+}
@@ -0,0 +1,45 @@
+package types
+
+type SubscriptionPlanRequest struct {
+	Name         string  `json:"name" binding:"required"`
+	PriceEGP     float64 `json:"priceEgp" binding:"required"`
+	Credits      int     `json:"credits" binding:"required"`
+	RewardPoints int     `json:"rewardPoints"`
+	TrialDays    int     `json:"trialDays"`
+	IsActive     *bool   `json:"isActive"`
+}
+
+type SubscriptionPlanResponse struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	PriceEGP     float64 `json:"priceEgp"`
+	Credits      int     `json:"credits"`
+	RewardPoints int     `json:"rewardPoints"`
+	TrialDays    int     `json:"trialDays"`
+	IsActive     bool    `json:"isActive"`
+	CreatedAt    string  `json:"createdAt"`
+}
+
+type SubscribeRequest struct {
+	PlanID        string `json:"planId" binding:"required"`
+	PaymentMethod string `json:"paymentMethod" binding:"required"`
+}
+
+type ChangePlanRequest struct {
+	PlanID string `json:"planId" binding:"required"`
+}
+
+type SubscriptionResponse struct {
+	ID                 string                   `json:"id"`
+	Plan               SubscriptionPlanResponse `json:"plan"`
+	Status             string                   `json:"status"`
+	CurrentPeriodStart string                   `json:"currentPeriodStart"`
+	CurrentPeriodEnd   string                   `json:"currentPeriodEnd"`
+	RenewsAt           *string                  `json:"renewsAt,omitempty"`
+	CancelAtPeriodEnd  bool                     `json:"cancelAtPeriodEnd"`
+	BalanceEGP         float64                  `json:"balanceEgp"`
+	FailedAttempts     int                      `json:"failedAttempts"`
+	PausedAt           *string                  `json:"pausedAt,omitempty"`
+	CancelledAt        *string                  `json:"cancelledAt,omitempty"`
+	CreatedAt          string                   `json:"createdAt"`
+}
@@ -0,0 +1,12 @@
+package types
+
+// Translation holds a resource's text in one locale. Credit packages only
+// use the name.
+type Translation struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Translations maps a locale such as "ar" to its text. The base name and
+// description are in the default locale.
+type Translations map[string]Translation
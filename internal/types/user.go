@@ -23,6 +23,7 @@ type DashboardStatsResponse struct {
 
 type UpdateRedemptionStatusRequest struct {
 	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"` // required when rejecting
 }
 
 type ManageCreditsRequest struct {
@@ -0,0 +1,28 @@
+package types
+
+type AddWishlistItemRequest struct {
+	ProductID string `json:"productId"`
+}
+
+type WishlistItemResponse struct {
+	Product    *ProductResponse `json:"product"`
+	InStock    bool             `json:"inStock"`
+	Affordable bool             `json:"affordable"`
+	AddedAt    string           `json:"addedAt"`
+}
+
+type NotificationResponse struct {
+	ID        string  `json:"id"`
+	Kind      string  `json:"kind"`
+	ProductID *string `json:"productId,omitempty"`
+	Message   string  `json:"message"`
+	ReadAt    *string `json:"readAt"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+type WishlistDemandItem struct {
+	ProductID     string `json:"productId"`
+	Name          string `json:"name"`
+	StockQuantity int    `json:"stockQuantity"`
+	Wishlisted    int64  `json:"wishlisted"`
+}